@@ -5,6 +5,17 @@ package config // import "github.com/dev7a/otelcol-con-spaneventtolog/spaneventt
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
 )
 
 // AttributeMappings defines how span event attributes should be mapped to log record fields.
@@ -14,7 +25,9 @@ type AttributeMappings struct {
 	Body string `mapstructure:"body"`
 
 	// SeverityNumber specifies the event attribute name to use for the log record severity number.
-	// If empty or the attribute doesn't exist, falls back to existing severity configuration.
+	// The attribute may be an int, a string holding an integer (e.g. "9"), or a double, which is
+	// truncated. If empty, the attribute doesn't exist or can't be parsed, falls back to existing
+	// severity configuration.
 	SeverityNumber string `mapstructure:"severity_number"`
 
 	// SeverityText specifies the event attribute name to use for the log record severity text.
@@ -26,19 +39,184 @@ type AttributeMappings struct {
 	EventName string `mapstructure:"event_name"`
 }
 
+// CorrelationKey defines a composite key written to each log record for joining with external systems.
+type CorrelationKey struct {
+	// Attribute is the log attribute name the rendered key is written to.
+	Attribute string `mapstructure:"attribute"`
+
+	// Template is the key template, using the same "{source:key}" references as RoutingAttributes
+	// (e.g. "{resource:service.name}:{event:request.id}"). References to missing attributes render
+	// as empty strings.
+	Template string `mapstructure:"template"`
+}
+
+// NumericAttributeFilter defines an inclusive range for a numeric event attribute.
+type NumericAttributeFilter struct {
+	// Key is the event attribute name. The attribute must be an int or a double.
+	Key string `mapstructure:"key"`
+
+	// Min is the inclusive lower bound of the range.
+	Min float64 `mapstructure:"min"`
+
+	// Max is the inclusive upper bound of the range.
+	Max float64 `mapstructure:"max"`
+}
+
+// AttributeRenameRule defines a regular expression based rename of copied attribute keys.
+type AttributeRenameRule struct {
+	// Pattern is the regular expression matched against attribute keys.
+	Pattern string `mapstructure:"pattern"`
+
+	// Replacement is the new key. It may reference capture groups from Pattern (e.g. "database.$1").
+	Replacement string `mapstructure:"replacement"`
+}
+
+// SeverityByNumericThreshold maps ranges of a numeric event attribute to severity levels.
+type SeverityByNumericThreshold struct {
+	// Attribute is the event attribute name. The attribute must be an int or a double.
+	Attribute string `mapstructure:"attribute"`
+
+	// Rules are the threshold to severity rules. They are evaluated from the highest threshold
+	// down and the first threshold exceeded by the attribute value wins.
+	Rules []NumericThresholdRule `mapstructure:"rules"`
+}
+
+// NumericThresholdRule maps values strictly greater than a threshold to a severity level.
+type NumericThresholdRule struct {
+	// Threshold is the exclusive lower bound of the rule.
+	Threshold float64 `mapstructure:"threshold"`
+
+	// Severity is the severity level for values above the threshold.
+	Severity string `mapstructure:"severity"`
+}
+
+// CollapseAttributesToJSON defines event attributes serialized into a single JSON object string attribute.
+type CollapseAttributesToJSON struct {
+	// TargetKey is the log attribute name the JSON string is written to.
+	TargetKey string `mapstructure:"target_key"`
+
+	// SourceKeys are the event attribute names to serialize. Missing attributes are omitted.
+	SourceKeys []string `mapstructure:"source_keys"`
+
+	// RemoveOriginals is a flag that indicates whether to remove the source attributes from the
+	// log record once they have been serialized.
+	RemoveOriginals bool `mapstructure:"remove_originals"`
+}
+
 // Config defines configuration for the span event to log connector.
 type Config struct {
 	// IncludeEventNames is the list of event names to include in the conversion from events to logs.
 	// If empty, all events will be included.
 	IncludeEventNames []string `mapstructure:"include_event_names"`
 
+	// ExcludeEventNames is the list of event names to exclude from the conversion from events to logs.
+	// Excluded events are skipped even if IncludeEventNames is empty. A name cannot be both included
+	// and excluded.
+	ExcludeEventNames []string `mapstructure:"exclude_event_names"`
+
+	// IncludeEventNamePatterns is a list of regular expressions matched against event names.
+	// Patterns must match the entire event name. An event is included if it matches either
+	// IncludeEventNames or any pattern. Patterns prefixed with "!" are negations: an event
+	// matching a negated pattern is excluded even if another rule included it.
+	// If only negated patterns are configured, all other events are included.
+	IncludeEventNamePatterns []string `mapstructure:"include_event_name_patterns"`
+
+	// IncludeEventNameGlobs is a list of glob patterns (e.g. "order.*") matched against event names
+	// with path.Match semantics. An event is included if it matches any glob, IncludeEventNames or
+	// IncludeEventNamePatterns.
+	IncludeEventNameGlobs []string `mapstructure:"include_event_name_globs"`
+
+	// RequireEventAttributes is a map from event attribute name to value. If set, only events
+	// carrying every listed attribute with the matching value are converted to logs. Values are
+	// compared according to the attribute type, so "true" matches both a bool attribute and the
+	// string "true".
+	RequireEventAttributes map[string]string `mapstructure:"require_event_attributes"`
+
+	// IncludeSpanKinds is the list of parent span kinds ("server", "client", "internal", "producer",
+	// "consumer" or "unspecified") whose events are converted to logs. If empty, events from spans
+	// of all kinds are converted.
+	IncludeSpanKinds []string `mapstructure:"include_span_kinds"`
+
+	// RequireSpanAttributes is a map from span attribute name to value. If set, only events from
+	// spans carrying every listed attribute with the matching value are converted to logs. An empty
+	// value only requires the attribute to be present.
+	RequireSpanAttributes map[string]string `mapstructure:"require_span_attributes"`
+
+	// IncludeSpanStatuses is the list of parent span status codes ("unset", "ok" or "error") whose
+	// events are converted to logs. If empty, events from spans with any status are converted.
+	IncludeSpanStatuses []string `mapstructure:"include_span_statuses"`
+
+	// SpanNamePatterns is a list of regular expressions matched against span names. Patterns must
+	// match the entire span name. If set, only events from spans whose name matches at least one
+	// pattern are converted to logs. If empty, events from all spans are converted.
+	SpanNamePatterns []string `mapstructure:"span_name_patterns"`
+
+	// NumericAttributeFilters is a list of numeric event attribute ranges. If set, only events with
+	// at least one of the attributes falling within its range are converted to logs. Events missing
+	// the attributes, or carrying non-numeric values, are skipped.
+	NumericAttributeFilters []NumericAttributeFilter `mapstructure:"numeric_attribute_filters"`
+
+	// TTLAttribute is the name of a numeric event attribute holding a time to live in seconds
+	// (e.g. "ttl_seconds"). Events older than their TTL when converted are dropped. Events without
+	// the attribute or without a timestamp are always converted.
+	TTLAttribute string `mapstructure:"ttl_attribute"`
+
+	// FilterConditions is a list of OTTL conditions evaluated against each span event in the
+	// ottlspanevent context (e.g. `attributes["error"] == true and name != "heartbeat"`). Only events
+	// for which all conditions are true are converted to logs; events for which a condition fails to
+	// evaluate are skipped. Applied after the event name and other event filters.
+	FilterConditions []string `mapstructure:"filter_conditions"`
+
+	// TransformStatements is a list of OTTL statements run in the ottllog context against each
+	// produced log record once it has been populated (e.g. `set(severity_text, "WARN") where
+	// attributes["retry"] > 3`). Statements that fail at runtime are logged and skipped, and the
+	// record is kept.
+	TransformStatements []string `mapstructure:"transform_statements"`
+
+	// MinEventTime and MaxEventTime bound the timestamps of the events converted to logs, as RFC 3339
+	// times (e.g. "2024-05-01T00:00:00Z"). Events outside the window are skipped, and bounds are
+	// inclusive. An empty value leaves that side of the window open.
+	MinEventTime string `mapstructure:"min_event_time"`
+	MaxEventTime string `mapstructure:"max_event_time"`
+
+	// ErrorTracesOnly is a flag that restricts the conversion to traces containing errors. If true,
+	// only events from traces with at least one span whose status is Error within the same batch
+	// are converted to logs, including events on the non-error spans of those traces.
+	ErrorTracesOnly bool `mapstructure:"error_traces_only"`
+
 	// IncludeSpanContext is a flag that indicates whether to include span context in the log record.
 	// If true, the following fields will be included in the log record:
 	// - TraceID
 	// - SpanID
 	// - TraceFlags
+	// - the "span.parent_id" attribute, holding the hex parent span ID of spans that aren't trace roots
 	IncludeSpanContext bool `mapstructure:"include_span_context"`
 
+	// SpanContextOnlyIfRemote is a flag that restricts span context injection to spans whose parent
+	// is remote (i.e. the entry point of a request from another service). Only applies when
+	// IncludeSpanContext is true. Remoteness is read from the span flags when the producer recorded
+	// it; otherwise SERVER and CONSUMER spans with a parent span ID are assumed to have a remote parent.
+	SpanContextOnlyIfRemote bool `mapstructure:"span_context_only_if_remote"`
+
+	// SkipSpanContextForPatterns is a list of regular expressions matched against event names.
+	// Patterns must match the entire event name. Span context is not injected for events whose
+	// name matches a pattern, such as high-cardinality names embedding IDs.
+	SkipSpanContextForPatterns []string `mapstructure:"skip_span_context_for_patterns"`
+
+	// IncludeTraceFlagsInt is a flag that indicates whether to set a "trace.flags" attribute to the
+	// integer value of the span's W3C trace flags (e.g. 1 when sampled). Only applies when span
+	// context is included.
+	IncludeTraceFlagsInt bool `mapstructure:"include_trace_flags_int"`
+
+	// IncludeSpanLinkCount is a flag that indicates whether to set a "span.link_count" attribute to
+	// the number of links on the span. Only applies when span context is included.
+	IncludeSpanLinkCount bool `mapstructure:"include_span_link_count"`
+
+	// SkipSpanContextAttribute is the name of an event attribute that lets individual events opt
+	// out of span context injection. When the attribute is truthy (true, "true", "1" or a non-zero
+	// int), span context is not injected for that event even if IncludeSpanContext is true.
+	SkipSpanContextAttribute string `mapstructure:"skip_span_context_attribute"`
+
 	// LogAttributesFrom is a list of attribute sources to include in the log record.
 	// Valid values are:
 	// - "event.attributes": includes all attributes from the span event
@@ -46,32 +224,522 @@ type Config struct {
 	// - "resource.attributes": includes all resource attributes
 	LogAttributesFrom []string `mapstructure:"log_attributes_from"`
 
+	// EventAttributeAllowlist, SpanAttributeAllowlist and ResourceAttributeAllowlist restrict the
+	// attributes copied from each source listed in LogAttributesFrom to the given keys, to control
+	// log size and cost. Keys are matched before renaming. An empty list copies every attribute.
+	EventAttributeAllowlist    []string `mapstructure:"event_attribute_allowlist"`
+	SpanAttributeAllowlist     []string `mapstructure:"span_attribute_allowlist"`
+	ResourceAttributeAllowlist []string `mapstructure:"resource_attribute_allowlist"`
+
+	// EventAttributeDenylist, SpanAttributeDenylist and ResourceAttributeDenylist list attribute
+	// keys never copied from each source (e.g. "http.request.header.authorization"). Keys are
+	// matched both before and after renaming. A denied key is excluded even if it is allowlisted.
+	// Denied event attributes are also left out wherever else event attributes are copied: the
+	// "attributes_map" body, the "span.event" map, collapsed JSON and aggregated "exception.causes".
+	EventAttributeDenylist    []string `mapstructure:"event_attribute_denylist"`
+	SpanAttributeDenylist     []string `mapstructure:"span_attribute_denylist"`
+	ResourceAttributeDenylist []string `mapstructure:"resource_attribute_denylist"`
+
 	// SeverityByEventName is a map from event name to severity level.
 	// If the event name is present in this map, the log record will have the mapped severity level.
-	// If not, the default severity level (Info) will be used.
+	// If not, the remaining severity sources apply, falling back to DefaultSeverity.
 	SeverityByEventName map[string]string `mapstructure:"severity_by_event_name"`
 
+	// PreserveOriginalSeverityText is the name of a log attribute that receives the severity text as
+	// it appeared on the event (e.g. "INFO"), before canonicalization. Only set when the severity was
+	// read from a text attribute via AttributeMappings.SeverityText or SeverityAttribute.
+	PreserveOriginalSeverityText string `mapstructure:"preserve_original_severity_text"`
+
+	// UnspecifiedSeverityText is the severity text set when the severity number resolves to
+	// unspecified (e.g. a mapped severity number of 0). It may be empty to leave the text empty,
+	// or "unspecified" to spell it out. If not set, the text resolved with the number is kept.
+	UnspecifiedSeverityText *string `mapstructure:"unspecified_severity_text"`
+
+	// SeverityBySpanKind is a map from parent span kind ("server", "client", "internal",
+	// "producer", "consumer" or "unspecified") to severity level. It is a low-precedence source,
+	// consulted only when no event-based source matched, before SeverityForUnmatchedEvents.
+	SeverityBySpanKind map[string]string `mapstructure:"severity_by_span_kind"`
+
+	// SeverityFromSpanStatus is a flag that indicates whether to derive the severity from the parent
+	// span's status code when no event-based source or SeverityBySpanKind matched. If true, an Error
+	// status maps to error and an Ok status to info, while an Unset status leaves the severity to
+	// the remaining sources. The span status message, when present, is added as a
+	// "span.status_message" attribute.
+	SeverityFromSpanStatus bool `mapstructure:"severity_from_span_status"`
+
+	// FlushErrorsImmediately is a flag that indicates whether to send error and fatal records to the
+	// next consumer in their own ConsumeLogs call, ahead of the other records of the batch, so that
+	// alerting on errors isn't delayed behind large batches. The total number of records is
+	// unchanged, and "spaneventtolog.converted_events" counts are split along with the records.
+	// The records keep their resources and scopes rather than moving to a distinct error scope,
+	// since the scope identifies the instrumentation that produced them; the separate ConsumeLogs
+	// call is what sets the error batch apart.
+	FlushErrorsImmediately bool `mapstructure:"flush_errors_immediately"`
+
+	// ErrorEscalationThreshold is the number of error-severity events a span may have before its
+	// error-severity log records are escalated to fatal. Escalation applies after severity
+	// resolution, regardless of the source the error severity came from, and counts the events
+	// folded into an aggregated exception record by AggregateExceptions. It updates the "level"
+	// attribute added by AddLevel. It runs before TransformStatements, so transforms see the
+	// escalated severity and can override it. Zero disables escalation.
+	ErrorEscalationThreshold int `mapstructure:"error_escalation_threshold"`
+
+	// SeverityForUnmatchedEvents is the severity level used for events that no other severity
+	// source (attribute mappings, attributes or event name mappings) matched. It is consulted last,
+	// before falling back to DefaultSeverity. If empty, this feature is disabled.
+	SeverityForUnmatchedEvents string `mapstructure:"severity_for_unmatched_events"`
+
+	// DowngradeSeverities is a map from severity level to the level it is replaced with after
+	// severity resolution (e.g. "fatal" to "error"), for a quieter pipeline without dropping records.
+	// Both the keys and the targets must be canonical severity levels other than "unspecified".
+	// Downgrades also apply to records escalated by ErrorEscalationThreshold, so a "fatal" downgrade
+	// holds for escalated records too.
+	DowngradeSeverities map[string]string `mapstructure:"downgrade_severities"`
+
+	// DefaultSeverity is the severity level events get when no severity source matched, replacing
+	// the built-in default. If empty, the default severity level is Info.
+	DefaultSeverity string `mapstructure:"default_severity"`
+
+	// DebugTraceSeverityResolution is a flag that indicates whether to record how the severity of
+	// each event was resolved. If true, a "severity_resolution" span event is added to the connector's
+	// own extraction span for every converted event, naming the severity source that matched and the
+	// resulting severity number and text. This is verbose and intended for debugging only.
+	DebugTraceSeverityResolution bool `mapstructure:"debug_trace_severity_resolution"`
+
+	// DetailedSpanAttributes is a flag that indicates whether to break down the counts recorded on
+	// the connector's own extraction span. If true, the span also carries the number of log records
+	// created per severity level ("logs_by_severity.<level>") and the number of events dropped per
+	// reason ("events_dropped.<reason>", e.g. event_name or span_filter). Events aggregated into
+	// an exception record count as processed, not dropped.
+	DetailedSpanAttributes bool `mapstructure:"detailed_span_attributes"`
+
 	// AddLevel is a flag that indicates whether to add a "level" attribute to the log record
 	// based on the severity text. If true and a "level" attribute doesn't already exist,
 	// the severity text will be copied to a "level" attribute.
 	AddLevel bool `mapstructure:"add_level"`
 
+	// HonorExplicitSeverityNumberAttribute is a flag that indicates whether to honor an explicit
+	// severity number set in the well-known "otel.log.severity_number" event attribute. If true and
+	// the attribute holds a valid severity number (1 to 24, as an int, a string or a double), it is
+	// used with the highest precedence, overriding a custom SeverityResolver, AttributeMappings and
+	// every other severity source.
+	HonorExplicitSeverityNumberAttribute bool `mapstructure:"honor_explicit_severity_number_attribute"`
+
 	// SeverityAttribute is the name of the event attribute to use for determining the severity level.
 	// If set, this takes precedence over SeverityByEventName. The attribute value must be a string
 	// matching one of the supported severity levels (case-insensitive).
 	SeverityAttribute string `mapstructure:"severity_attribute"`
 
+	// SeverityAliases is a map from custom severity words (e.g. "notice" or "critical") to one of the
+	// canonical severity levels other than "unspecified" (e.g. "info3" or "fatal2"). Aliases are
+	// matched case-insensitively wherever a severity level is read from event attributes.
+	SeverityAliases map[string]string `mapstructure:"severity_aliases"`
+
+	// Log4jSeverityMode is a flag that indicates whether severity levels read from event attributes
+	// (SeverityAttribute and AttributeMappings.SeverityText) also accept the Log4j/Logback levels.
+	// If true, "ALL" maps to trace and "OFF", which ranks above FATAL, maps to fatal4; the other
+	// Java levels (TRACE, DEBUG, INFO, WARN, ERROR, FATAL) map to their canonical severities.
+	Log4jSeverityMode bool `mapstructure:"log4j_severity_mode"`
+
+	// SeverityByAttributePresence is a map from event attribute key to severity level.
+	// If an event carries one of the keys (e.g. "error.message"), the log record will have the
+	// mapped severity level, regardless of the attribute value. Event attributes are checked in
+	// order and the first present key wins. This takes precedence over SeverityByEventName but
+	// not over SeverityAttribute.
+	SeverityByAttributePresence map[string]string `mapstructure:"severity_by_attribute_presence"`
+
+	// SeverityByNumericThreshold sets the severity from a numeric event attribute compared against
+	// thresholds (e.g. response_time_ms above 1000 as warn, above 5000 as error). This takes
+	// precedence over SeverityByEventName but not over SeverityByAttributePresence.
+	SeverityByNumericThreshold SeverityByNumericThreshold `mapstructure:"severity_by_numeric_threshold"`
+
 	// AttributeMappings defines how span event attributes should be mapped to log record fields.
 	// These mappings take precedence over other configuration options and fall back to existing
 	// behavior when the specified attributes don't exist.
 	AttributeMappings AttributeMappings `mapstructure:"attribute_mappings"`
 
+	// DoubleAttributePrecision is the number of decimal places that double attribute values are
+	// rounded to when copying event and span attributes to the log record.
+	// If nil or negative, doubles are copied verbatim.
+	DoubleAttributePrecision *int `mapstructure:"double_attribute_precision"`
+
+	// BytesAttributeEncoding controls how bytes values are handled when copying event and span
+	// attributes to the log record. Valid values are:
+	// - "raw" (default): copies bytes values verbatim
+	// - "base64": converts bytes values to standard base64 strings
+	// - "hex": converts bytes values to lowercase hex strings
+	// - "drop": removes bytes values
+	BytesAttributeEncoding string `mapstructure:"bytes_attribute_encoding"`
+
+	// BodyBytesEncoding controls how a body attribute holding a bytes value is turned into the log
+	// record body. Valid values are:
+	// - "" (default): bytes values are not used, and the body falls back to the event name
+	// - "base64": the body is the standard base64 string of the bytes
+	// - "hex": the body is the lowercase hex string of the bytes
+	BodyBytesEncoding string `mapstructure:"body_bytes_encoding"`
+
+	// DropEmptyStringAttributes is a flag that indicates whether to skip string attributes whose
+	// value is empty when copying event and span attributes to the log record.
+	DropEmptyStringAttributes bool `mapstructure:"drop_empty_string_attributes"`
+
+	// DropEmptyCollectionAttributes is a flag that indicates whether to also skip map and slice
+	// attributes that are empty, including those left empty by other transformations.
+	DropEmptyCollectionAttributes bool `mapstructure:"drop_empty_collection_attributes"`
+
+	// FlattenAttributes is a flag that indicates whether map and slice values of copied attributes
+	// are flattened into one attribute per nested value, with dotted keys for map entries (e.g.
+	// "http.status_code") and indexed keys for slice elements (e.g. "tags.0"). Empty maps and slices
+	// are kept as they are. Flattened keys are subject to MaxKeyDepth.
+	FlattenAttributes bool `mapstructure:"flatten_attributes"`
+
+	// StringifyAllAttributes is a flag that indicates whether the values of copied event and span
+	// attributes are converted to their string form, e.g. 42 becomes "42" and true becomes "true".
+	// Maps and slices become JSON strings, unless flattened with FlattenAttributes.
+	StringifyAllAttributes bool `mapstructure:"stringify_all_attributes"`
+
+	// MaxKeyDepth is the maximum number of dot-separated segments allowed in the keys of event and
+	// span attributes copied to the log record. Longer keys are truncated to their first MaxKeyDepth
+	// segments, or dropped if DropKeysExceedingDepth is set. The depth includes the segments of
+	// SpanAttributePrefix. Zero disables the limit.
+	MaxKeyDepth int `mapstructure:"max_key_depth"`
+
+	// DropKeysExceedingDepth is a flag that indicates whether attributes with keys deeper than
+	// MaxKeyDepth are dropped instead of truncated.
+	DropKeysExceedingDepth bool `mapstructure:"drop_keys_exceeding_depth"`
+
+	// AttributeRenameRules is an ordered list of rules renaming the keys of event and span attributes
+	// copied to the log record. For each key, the first rule whose pattern matches is applied.
+	AttributeRenameRules []AttributeRenameRule `mapstructure:"attribute_rename_rules"`
+
+	// AttributeRenames maps attribute keys to the keys they are moved to once all attributes have
+	// been copied to the log record, whatever their source. A value already present under the
+	// target key is overwritten. Renames are not chained: each value is moved at most once. A target
+	// must not be listed in any of the attribute denylists.
+	AttributeRenames map[string]string `mapstructure:"attribute_renames"`
+
+	// HeartbeatMetric is a flag that indicates whether to periodically record heartbeat metrics
+	// through the collector's MeterProvider while the connector is running. If true, a
+	// "spaneventtolog.heartbeats" counter and a "spaneventtolog.heartbeat.events_processed" gauge
+	// holding the cumulative number of events processed are recorded every HeartbeatInterval.
+	HeartbeatMetric bool `mapstructure:"heartbeat_metric"`
+
+	// HeartbeatInterval is the interval between heartbeats. Only applies when HeartbeatMetric is true.
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+
+	// MaxTotalAttributes caps the number of attributes added to log records across a whole traces
+	// batch, bounding memory under pathological batches. Once the cap is reached, further attributes,
+	// including sequence attributes and aggregated exception causes, are skipped rather than copied
+	// and counted in the records' DroppedAttributesCount and in the
+	// "spaneventtolog.truncated_attributes" metric. Bodies, severities and span context IDs are
+	// always kept. Zero disables the cap.
+	MaxTotalAttributes int `mapstructure:"max_total_attributes"`
+
+	// AccumulateDroppedCounts is a flag that indicates whether to add the number of attributes the
+	// span event dropped at instrumentation time to the log record's DroppedAttributesCount, on top
+	// of any attributes dropped by the connector itself.
+	AccumulateDroppedCounts bool `mapstructure:"accumulate_dropped_counts"`
+
+	// ScopePerEventName is a flag that indicates whether log records should be grouped into
+	// one ScopeLogs per distinct event name instead of inheriting the source instrumentation scope.
+	// If true, the scope name of each ScopeLogs will be the name of the events it contains.
+	ScopePerEventName bool `mapstructure:"scope_per_event_name"`
+
+	// ScopeByAttribute is the name of an event or resource attribute whose value names the output
+	// ScopeLogs, so that records with the same value are grouped together. The event attribute is
+	// looked up first, then the resource attribute. Records carrying neither are placed as if
+	// ScopeByAttribute were unset. Takes precedence over ScopePerEventName.
+	ScopeByAttribute string `mapstructure:"scope_by_attribute"`
+
+	// OutputScopeVersionFromAttribute is the name of an event or resource attribute whose value is
+	// the version of the output ScopeLogs (e.g. "service.version"), so that records with different
+	// values are grouped apart. The event attribute is looked up first, then the resource attribute.
+	// Records carrying neither keep the version of their scope.
+	OutputScopeVersionFromAttribute string `mapstructure:"output_scope_version_from_attribute"`
+
+	// IncludeStatusCode is a flag that indicates whether to add the parent span's status code
+	// to the log record. If true, a "span.status_code" attribute will be set to the string form
+	// of the status code ("Unset", "Ok" or "Error"), along with a "span.status_message" attribute
+	// when the status message is not empty.
+	IncludeStatusCode bool `mapstructure:"include_status_code"`
+
+	// IncludeCollectorHostname is a flag that indicates whether to add the hostname of the collector
+	// running the connector to each log record, as a "collector.hostname" attribute. The hostname is
+	// resolved once when the connector starts.
+	IncludeCollectorHostname bool `mapstructure:"include_collector_hostname"`
+
+	// IncludeIsRoot is a flag that indicates whether to record if the parent span is the root of its
+	// trace. If true, a "span.is_root" bool attribute will be set, true when the span has no parent.
+	IncludeIsRoot bool `mapstructure:"include_is_root"`
+
+	// IncludeSpanDuration is a flag that indicates whether to record the duration of the parent
+	// span. If true, a "span.duration_<unit>" attribute will be set for spans that have ended, in the
+	// unit selected by SpanDurationUnit.
+	IncludeSpanDuration bool `mapstructure:"include_span_duration"`
+
+	// SpanDurationUnit is the unit of the span duration attribute. Valid values are:
+	// - "ms" (default): milliseconds, as a double
+	// - "ns": nanoseconds, as an int
+	// - "s": seconds, as a double
+	SpanDurationUnit string `mapstructure:"span_duration_unit"`
+
+	// AggregateExceptions is a flag that indicates whether to combine the "exception" events of a
+	// span into a single log record. If true, the first exception event produces the record, with
+	// its "exception.message" as the body, and the attributes of each further exception event
+	// (e.g. chained causes) are appended as maps to an "exception.causes" slice attribute.
+	AggregateExceptions bool `mapstructure:"aggregate_exceptions"`
+
+	// ParseStacktrace is a flag that indicates whether to split a copied "exception.stacktrace"
+	// event attribute into a slice of frames. If true, the non-empty lines of the stacktrace
+	// will be stored in an "exception.stacktrace.frames" slice attribute.
+	ParseStacktrace bool `mapstructure:"parse_stacktrace"`
+
+	// DropRawStacktrace is a flag that indicates whether to remove the raw "exception.stacktrace"
+	// attribute once it has been parsed into frames. Only applies when ParseStacktrace is true.
+	DropRawStacktrace bool `mapstructure:"drop_raw_stacktrace"`
+
+	// ShortenExceptionType is a flag that indicates whether to add the short class name of a copied
+	// "exception.type" event attribute. If true, the last dot-separated segment of the type
+	// (e.g. "NullPointerException" for "java.lang.NullPointerException") will be stored in an
+	// "error.type.short" attribute alongside the full value.
+	ShortenExceptionType bool `mapstructure:"shorten_exception_type"`
+
+	// IncludeSpanNameHash is a flag that indicates whether to add a stable short hash of the parent
+	// span's name to the log record. If true, a "span.name_hash" attribute will be set to the
+	// hex-encoded 32-bit FNV-1a hash of the span name, allowing grouping by span name without
+	// storing high-cardinality names.
+	IncludeSpanNameHash bool `mapstructure:"include_span_name_hash"`
+
+	// GenerateRecordID is a flag that indicates whether to add a deterministic ID to each log record.
+	// If true, a "log.record.id" attribute will be set to the hex-encoded 128-bit FNV-1a hash of the
+	// trace ID, span ID, event index and event name, so replays produce the same IDs and can be
+	// deduplicated downstream.
+	GenerateRecordID bool `mapstructure:"generate_record_id"`
+
+	// AnnotateSourceScope is a flag that indicates whether to record the instrumentation scope
+	// the event was read from. If true, a "spaneventtolog.source_scope" attribute will be set
+	// to the name of the source scope.
+	AnnotateSourceScope bool `mapstructure:"annotate_source_scope"`
+
+	// AnnotateSourceIndex is a flag that indicates whether to record the position of the source
+	// span in the incoming traces, for diagnosing grouping issues. If true, the index of the
+	// ResourceSpans will be set in a "spaneventtolog.source_resource_index" attribute and the index
+	// of the span within its ScopeSpans in a "spaneventtolog.source_span_index" attribute.
+	AnnotateSourceIndex bool `mapstructure:"annotate_source_index"`
+
+	// AnnotateScopeAttributeCount is a flag that indicates whether to record the number of attributes
+	// on the instrumentation scope the event was read from. If true, an "otel.scope.attribute_count"
+	// attribute will be set on the log record.
+	AnnotateScopeAttributeCount bool `mapstructure:"annotate_scope_attribute_count"`
+
+	// SchemaVersionAttribute is the name of a log attribute set to SchemaVersion on each record,
+	// letting consumers handle changes in the shape of the connector's output. If empty, no
+	// schema version is stamped.
+	SchemaVersionAttribute string `mapstructure:"schema_version_attribute"`
+
+	// SchemaVersion is the schema version value stamped via SchemaVersionAttribute.
+	SchemaVersion string `mapstructure:"schema_version"`
+
+	// SequenceAttribute is the name of a log attribute set to a sequence number reflecting the order
+	// records were emitted in within a batch, starting at 0. If empty, no sequence number is set.
+	SequenceAttribute string `mapstructure:"sequence_attribute"`
+
+	// IncludeEmissionSequence is a flag that indicates whether to number emitted records across
+	// batches for strict ordering. If true, a "spaneventtolog.emission_seq" attribute will be set to
+	// a counter that starts at zero and increases with every record for the lifetime of the connector.
+	IncludeEmissionSequence bool `mapstructure:"include_emission_sequence"`
+
+	// AnnotateBatchSize is a flag that indicates whether to record the size of the source batch.
+	// If true, a "spaneventtolog.batch_span_count" attribute will be set to the total number of
+	// spans in the traces batch the event was read from.
+	AnnotateBatchSize bool `mapstructure:"annotate_batch_size"`
+
+	// AnnotateResourceEventCount is a flag that indicates whether to record the number of converted
+	// events per resource. If true, a "spaneventtolog.converted_events" resource attribute will be
+	// set on each ResourceLogs to the number of log records it holds.
+	AnnotateResourceEventCount bool `mapstructure:"annotate_resource_event_count"`
+
+	// IncludeServiceVersion is a flag that indicates whether to copy the "service.name" and
+	// "service.version" resource attributes onto each log record, even when resource attributes
+	// are not included via LogAttributesFrom.
+	IncludeServiceVersion bool `mapstructure:"include_service_version"`
+
+	// DedupeWithinBatch is a flag that indicates whether to drop log records with the same body,
+	// severity and attributes as an earlier record of the same batch, e.g. produced by retried spans.
+	// Timestamps and trace context are not compared, nor are the attributes that differ for every
+	// record (SequenceAttribute, IncludeEmissionSequence, GenerateRecordID and AnnotateSourceIndex).
+	// The kept record retains its sequence numbers, so dropped duplicates leave gaps in them.
+	DedupeWithinBatch bool `mapstructure:"dedupe_within_batch"`
+
+	// InjectConnectorScope is a flag that indicates whether to mark the output as produced by the
+	// connector. If true, each ResourceLogs gets an additional ScopeLogs named "spaneventtolog",
+	// holding a single marker record with a "spaneventtolog.marker" attribute and the connector
+	// component ID, apart from the converted event records.
+	InjectConnectorScope bool `mapstructure:"inject_connector_scope"`
+
+	// StaticResource is a set of attributes forming a synthetic resource that all log records are
+	// attributed to, in a single ResourceLogs. If set, the source resources are not copied to the
+	// output, whatever LogAttributesFrom says.
+	StaticResource map[string]string `mapstructure:"static_resource"`
+
+	// SpanAttributePrefix is prepended to the keys of span attributes copied to the log record, so
+	// that they can't collide with event attributes (e.g. "span."). Empty by default.
+	SpanAttributePrefix string `mapstructure:"span_attribute_prefix"`
+
+	// ResourceAttributePrefix is prepended to the keys of resource attributes copied to the log
+	// record via IncludeServiceVersion (e.g. "resource."). Empty by default.
+	ResourceAttributePrefix string `mapstructure:"resource_attribute_prefix"`
+
+	// BodySliceJoin is the separator used to join a body attribute holding a slice of strings into a
+	// single body line. Slices holding non-string values fall back to the event name, without
+	// trying SecondaryBodyAttribute. If empty, slice body attributes are not used.
+	BodySliceJoin string `mapstructure:"body_slice_join"`
+
+	// SecondaryBodyAttribute is the event attribute used for the log record body when the
+	// AttributeMappings.Body attribute is missing, before falling back to the event name.
+	SecondaryBodyAttribute string `mapstructure:"secondary_body_attribute"`
+
+	// DefaultBody is the log record body used when the event has an empty name and no body
+	// could be taken from AttributeMappings.Body or SecondaryBodyAttribute. If empty, the body is left empty.
+	DefaultBody string `mapstructure:"default_body"`
+
+	// IncludeNormalizedEventName is a flag that indicates whether to add a normalized form of the
+	// event name for grouping. If true, an "event.name.normalized" attribute will be set to the
+	// trimmed, lowercased event name, while the body keeps the original name.
+	IncludeNormalizedEventName bool `mapstructure:"include_normalized_event_name"`
+
+	// BodyExtractPattern is a regular expression with named capture groups matched against the
+	// string body of each log record (e.g. `user=(?P<user>\S+) action=(?P<action>\S+)`). Each named
+	// group that participates in the match is promoted to a log attribute of the same name. If
+	// empty, or if the body doesn't match, no attributes are extracted.
+	BodyExtractPattern string `mapstructure:"body_extract_pattern"`
+
+	// PreserveEventAsMap is a flag that indicates whether to copy the entire span event into a single
+	// nested map attribute for lossless round-tripping. If true, a "span.event" map attribute will
+	// hold the event "name", "time_unix_nano", "attributes" (with their structure kept) and
+	// "dropped_attributes_count".
+	PreserveEventAsMap bool `mapstructure:"preserve_event_as_map"`
+
+	// CollapseAttributesToJSON serializes a set of event attributes into a single JSON object
+	// string attribute. If no source keys are configured, nothing is collapsed.
+	CollapseAttributesToJSON CollapseAttributesToJSON `mapstructure:"collapse_attributes_to_json"`
+
+	// CorrelationKey defines a composite key built from event, span and resource attributes that is
+	// written to each log record. If the template is empty, no correlation key is written.
+	CorrelationKey CorrelationKey `mapstructure:"correlation_key"`
+
+	// BodyMode controls how the log record body is built. Valid values are:
+	// - "event_name" (default): uses AttributeMappings.Body if present, falling back to the event name
+	// - "attributes_map": sets the body to a map holding all event attributes; event attributes are
+	//   then not copied to the log record attributes
+	BodyMode string `mapstructure:"body_mode"`
+
+	// SemConvMode renames well-known legacy event attribute keys to the current OTel semantic
+	// conventions when they are copied. Valid values are:
+	// - "none" (default): attributes are copied verbatim
+	// - "http": e.g. "http.method" becomes "http.request.method" and "http.url" becomes "url.full"
+	// - "db": e.g. "db.statement" becomes "db.query.text" and "db.name" becomes "db.namespace"
+	// - "exception": e.g. "error.message" becomes "exception.message" and "error.stack" becomes
+	//   "exception.stacktrace"
+	// An attribute already present under the semantic-convention key is kept.
+	SemConvMode string `mapstructure:"semconv_mode"`
+
+	// TimestampSources is an ordered list of sources for the log record timestamp. The first source
+	// yielding a non-zero timestamp is used. Valid values are:
+	// - "attribute:<key>": the event attribute <key>, as Unix nanoseconds (int) or an RFC 3339 string
+	// - "event_time": the span event timestamp
+	// - "span_start": the parent span start timestamp
+	// - "span_end": the parent span end timestamp
+	// - "now": the time the event is converted
+	// If empty or no source yields a timestamp, the span event timestamp is used.
+	TimestampSources []string `mapstructure:"timestamp_sources"`
+
+	// ObservedTimestamp selects the observed timestamp of the log records. Valid values are:
+	// - "now" (default): the time the event is converted
+	// - "event": the span event timestamp, e.g. for replayed or backfilled data
+	// - "none": the observed timestamp is left unset
+	ObservedTimestamp string `mapstructure:"observed_timestamp"`
+
+	// TimestampFallback is a flag that indicates whether log records get a non-zero timestamp when
+	// the span event timestamp is unset. If true, the parent span start timestamp is used instead,
+	// or the current time if the span has no start timestamp either. Enabled by default.
+	TimestampFallback bool `mapstructure:"timestamp_fallback"`
+
+	// RoutingAttributes is a map from log attribute name to a value template. Each log record
+	// gets the rendered value, which downstream routing components can use to fan out logs.
+	// Templates mix literal text with attribute references of the form "{source:key}", where
+	// source is one of "resource", "span" or "event" (e.g. "{resource:tenant.id}").
+	// References to missing attributes render as empty strings.
+	RoutingAttributes map[string]string `mapstructure:"routing_attributes"`
+
+	// EmitAbsenceLogForSpanAttribute is a map from span attribute name to value identifying spans of
+	// interest. If a span carries all of the listed attributes with matching values but none of its
+	// events pass the event filters, an info log record with the body "no matching events" is emitted
+	// for that span. If empty, no absence logs are emitted.
+	EmitAbsenceLogForSpanAttribute map[string]string `mapstructure:"emit_absence_log_for_span_attribute"`
+
+	// EmitFullyFilteredSpanLog is a flag that indicates whether to note spans whose events were all
+	// filtered out. If true, a debug log record with the body "all events filtered" and a
+	// "spaneventtolog.filtered_events" attribute holding the number of dropped events is emitted
+	// for each span that had events but none passed the event filters.
+	EmitFullyFilteredSpanLog bool `mapstructure:"emit_fully_filtered_span_log"`
+
 	// prevent unkeyed literal initialization
 	_ struct{}
 }
 
+// validSpanKinds lists the span kind names accepted in the configuration.
+var validSpanKinds = map[string]bool{
+	"unspecified": true,
+	"internal":    true,
+	"server":      true,
+	"client":      true,
+	"producer":    true,
+	"consumer":    true,
+}
+
+// validSeverities is the set of severity levels accepted in the configuration.
+var validSeverities = map[string]bool{
+	"trace":       true,
+	"trace2":      true,
+	"trace3":      true,
+	"trace4":      true,
+	"debug":       true,
+	"debug2":      true,
+	"debug3":      true,
+	"debug4":      true,
+	"info":        true,
+	"info2":       true,
+	"info3":       true,
+	"info4":       true,
+	"warn":        true,
+	"warn2":       true,
+	"warn3":       true,
+	"warn4":       true,
+	"error":       true,
+	"error2":      true,
+	"error3":      true,
+	"error4":      true,
+	"fatal":       true,
+	"fatal2":      true,
+	"fatal3":      true,
+	"fatal4":      true,
+	"unspecified": true,
+}
+
 // Validate checks if the connector configuration is valid.
 func (c *Config) Validate() error {
+	if len(c.ExcludeEventNames) > 0 {
+		included := make(map[string]struct{}, len(c.IncludeEventNames))
+		for _, name := range c.IncludeEventNames {
+			included[name] = struct{}{}
+		}
+		for _, name := range c.ExcludeEventNames {
+			if _, exists := included[name]; exists {
+				return fmt.Errorf("event name %s is both included and excluded", name)
+			}
+		}
+	}
+
 	validSources := map[string]bool{
 		"event.attributes":    true,
 		"span.attributes":     true,
@@ -84,32 +752,190 @@ func (c *Config) Validate() error {
 		}
 	}
 
-	validSeverities := map[string]bool{
-		"trace":       true,
-		"trace2":      true,
-		"trace3":      true,
-		"trace4":      true,
-		"debug":       true,
-		"debug2":      true,
-		"debug3":      true,
-		"debug4":      true,
-		"info":        true,
-		"info2":       true,
-		"info3":       true,
-		"info4":       true,
-		"warn":        true,
-		"warn2":       true,
-		"warn3":       true,
-		"warn4":       true,
-		"error":       true,
-		"error2":      true,
-		"error3":      true,
-		"error4":      true,
-		"fatal":       true,
-		"fatal2":      true,
-		"fatal3":      true,
-		"fatal4":      true,
-		"unspecified": true,
+	for _, filter := range c.NumericAttributeFilters {
+		if filter.Key == "" {
+			return fmt.Errorf("numeric attribute filter key must not be empty")
+		}
+		if filter.Min > filter.Max {
+			return fmt.Errorf("invalid numeric attribute filter for %s: min %v is greater than max %v", filter.Key, filter.Min, filter.Max)
+		}
+	}
+
+	if c.ErrorEscalationThreshold < 0 {
+		return fmt.Errorf("error escalation threshold must not be negative: %d", c.ErrorEscalationThreshold)
+	}
+
+	if c.HeartbeatMetric && c.HeartbeatInterval <= 0 {
+		return fmt.Errorf("heartbeat interval must be positive when the heartbeat metric is enabled: %s", c.HeartbeatInterval)
+	}
+
+	if c.MaxTotalAttributes < 0 {
+		return fmt.Errorf("max total attributes must not be negative: %d", c.MaxTotalAttributes)
+	}
+
+	if c.MaxKeyDepth < 0 {
+		return fmt.Errorf("max key depth must not be negative: %d", c.MaxKeyDepth)
+	}
+
+	switch c.BytesAttributeEncoding {
+	case "", "raw", "base64", "hex", "drop":
+	default:
+		return fmt.Errorf("invalid bytes attribute encoding: %s", c.BytesAttributeEncoding)
+	}
+
+	if _, err := ParseFilterConditions(c.FilterConditions, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return err
+	}
+
+	if _, err := ParseTransformStatements(c.TransformStatements, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return err
+	}
+
+	minEventTime, maxEventTime, err := c.EventTimeWindow()
+	if err != nil {
+		return err
+	}
+	if !minEventTime.IsZero() && !maxEventTime.IsZero() && minEventTime.After(maxEventTime) {
+		return fmt.Errorf("min event time %s must not be after max event time %s", c.MinEventTime, c.MaxEventTime)
+	}
+
+	switch c.SpanDurationUnit {
+	case "", "ns", "ms", "s":
+	default:
+		return fmt.Errorf("invalid span duration unit: %s", c.SpanDurationUnit)
+	}
+
+	switch c.ObservedTimestamp {
+	case "", "now", "event", "none":
+	default:
+		return fmt.Errorf("invalid observed timestamp: %s", c.ObservedTimestamp)
+	}
+
+	switch c.BodyBytesEncoding {
+	case "", "base64", "hex":
+	default:
+		return fmt.Errorf("invalid body bytes encoding: %s", c.BodyBytesEncoding)
+	}
+
+	switch c.BodyMode {
+	case "", "event_name", "attributes_map":
+	default:
+		return fmt.Errorf("invalid body mode: %s", c.BodyMode)
+	}
+
+	switch c.SemConvMode {
+	case "", "none", "http", "db", "exception":
+	default:
+		return fmt.Errorf("invalid semconv mode: %s", c.SemConvMode)
+	}
+
+	for _, pattern := range c.IncludeEventNamePatterns {
+		if _, err := CompileEventNamePattern(strings.TrimPrefix(pattern, "!")); err != nil {
+			return fmt.Errorf("invalid include event name pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, glob := range c.IncludeEventNameGlobs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid include event name glob %q: %w", glob, err)
+		}
+	}
+
+	for _, kind := range c.IncludeSpanKinds {
+		if !validSpanKinds[kind] {
+			return fmt.Errorf("invalid span kind: %s", kind)
+		}
+	}
+
+	for _, status := range c.IncludeSpanStatuses {
+		switch status {
+		case "unset", "ok", "error":
+		default:
+			return fmt.Errorf("invalid span status: %s", status)
+		}
+	}
+
+	for _, pattern := range c.SpanNamePatterns {
+		if _, err := CompileEventNamePattern(pattern); err != nil {
+			return fmt.Errorf("invalid span name pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range c.SkipSpanContextForPatterns {
+		if _, err := CompileEventNamePattern(pattern); err != nil {
+			return fmt.Errorf("invalid skip span context pattern %q: %w", pattern, err)
+		}
+	}
+
+	validTimestampSources := map[string]bool{
+		"event_time": true,
+		"span_start": true,
+		"span_end":   true,
+		"now":        true,
+	}
+
+	for _, source := range c.TimestampSources {
+		if key, found := strings.CutPrefix(source, "attribute:"); found {
+			if key == "" {
+				return fmt.Errorf("invalid timestamp source: %s", source)
+			}
+			continue
+		}
+		if !validTimestampSources[source] {
+			return fmt.Errorf("invalid timestamp source: %s", source)
+		}
+	}
+
+	if c.BodyExtractPattern != "" {
+		re, err := regexp.Compile(c.BodyExtractPattern)
+		if err != nil {
+			return fmt.Errorf("invalid body extract pattern %q: %w", c.BodyExtractPattern, err)
+		}
+		if !hasNamedGroup(re) {
+			return fmt.Errorf("body extract pattern %q has no named capture groups", c.BodyExtractPattern)
+		}
+	}
+
+	for _, rule := range c.AttributeRenameRules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid attribute rename pattern %q: %w", rule.Pattern, err)
+		}
+	}
+
+	for source, target := range c.AttributeRenames {
+		if target == "" {
+			return fmt.Errorf("attribute rename target for %s must not be empty", source)
+		}
+		for _, denylist := range [][]string{c.EventAttributeDenylist, c.SpanAttributeDenylist, c.ResourceAttributeDenylist} {
+			for _, denied := range denylist {
+				if target == denied {
+					return fmt.Errorf("attribute rename target %s for %s is denylisted", target, source)
+				}
+			}
+		}
+	}
+
+	for key, tmpl := range c.RoutingAttributes {
+		if _, err := CompileAttributeTemplate(tmpl); err != nil {
+			return fmt.Errorf("invalid routing attribute template for %s: %w", key, err)
+		}
+	}
+
+	if c.SchemaVersionAttribute != "" && c.SchemaVersion == "" {
+		return fmt.Errorf("schema version must be set when a schema version attribute is configured")
+	}
+
+	if len(c.CollapseAttributesToJSON.SourceKeys) > 0 && c.CollapseAttributesToJSON.TargetKey == "" {
+		return fmt.Errorf("collapse attributes to json target key must be set when source keys are configured")
+	}
+
+	if c.CorrelationKey.Template != "" {
+		if c.CorrelationKey.Attribute == "" {
+			return fmt.Errorf("correlation key attribute must be set when a template is configured")
+		}
+		if _, err := CompileAttributeTemplate(c.CorrelationKey.Template); err != nil {
+			return fmt.Errorf("invalid correlation key template: %w", err)
+		}
 	}
 
 	for eventName, severity := range c.SeverityByEventName {
@@ -118,5 +944,168 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for kind, severity := range c.SeverityBySpanKind {
+		if !validSpanKinds[kind] {
+			return fmt.Errorf("invalid span kind: %s", kind)
+		}
+		if !validSeverities[severity] {
+			return fmt.Errorf("invalid severity level for span kind %s: %s", kind, severity)
+		}
+	}
+
+	if c.SeverityForUnmatchedEvents != "" && !validSeverities[c.SeverityForUnmatchedEvents] {
+		return fmt.Errorf("invalid severity level for unmatched events: %s", c.SeverityForUnmatchedEvents)
+	}
+
+	for alias, target := range c.SeverityAliases {
+		if !validSeverities[target] || target == "unspecified" {
+			return fmt.Errorf("invalid severity alias target for %s: %s", alias, target)
+		}
+	}
+
+	for severity, target := range c.DowngradeSeverities {
+		if !validSeverities[severity] || severity == "unspecified" {
+			return fmt.Errorf("invalid severity level to downgrade: %s", severity)
+		}
+		if !validSeverities[target] || target == "unspecified" {
+			return fmt.Errorf("invalid severity downgrade target for %s: %s", severity, target)
+		}
+	}
+
+	if c.DefaultSeverity != "" && !validSeverities[c.DefaultSeverity] {
+		return fmt.Errorf("invalid default severity level: %s", c.DefaultSeverity)
+	}
+
+	if len(c.SeverityByNumericThreshold.Rules) > 0 && c.SeverityByNumericThreshold.Attribute == "" {
+		return fmt.Errorf("severity by numeric threshold attribute must be set when rules are configured")
+	}
+	for _, rule := range c.SeverityByNumericThreshold.Rules {
+		if !validSeverities[rule.Severity] {
+			return fmt.Errorf("invalid severity level for numeric threshold %v: %s", rule.Threshold, rule.Severity)
+		}
+	}
+
+	for key, severity := range c.SeverityByAttributePresence {
+		if !validSeverities[severity] {
+			return fmt.Errorf("invalid severity level for attribute %s: %s", key, severity)
+		}
+	}
+
 	return nil
 }
+
+// CompileEventNamePattern compiles a regular expression that must match an entire event name.
+func CompileEventNamePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// ParseFilterConditions parses OTTL conditions in the span event context, with the standard OTTL
+// converters available.
+func ParseFilterConditions(conditions []string, telemetrySettings component.TelemetrySettings) ([]*ottl.Condition[ottlspanevent.TransformContext], error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	parser, err := ottlspanevent.NewParser(ottlfuncs.StandardConverters[ottlspanevent.TransformContext](), telemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter condition parser: %w", err)
+	}
+	parsed, err := parser.ParseConditions(conditions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter conditions: %w", err)
+	}
+	return parsed, nil
+}
+
+// ParseTransformStatements parses OTTL statements in the log context, with the standard OTTL
+// functions available.
+func ParseTransformStatements(statements []string, telemetrySettings component.TelemetrySettings) ([]*ottl.Statement[ottllog.TransformContext], error) {
+	if len(statements) == 0 {
+		return nil, nil
+	}
+	parser, err := ottllog.NewParser(ottlfuncs.StandardFuncs[ottllog.TransformContext](), telemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transform statement parser: %w", err)
+	}
+	parsed, err := parser.ParseStatements(statements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform statements: %w", err)
+	}
+	return parsed, nil
+}
+
+// EventTimeWindow parses MinEventTime and MaxEventTime. An unset bound is returned as the zero time.
+func (c *Config) EventTimeWindow() (time.Time, time.Time, error) {
+	var bounds [2]time.Time
+	for i, value := range []string{c.MinEventTime, c.MaxEventTime} {
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid event time %q: %w", value, err)
+		}
+		bounds[i] = t
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// hasNamedGroup determines if the regular expression has at least one named capture group.
+func hasNamedGroup(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplatePart is a segment of a compiled attribute template. It is either literal text,
+// or a reference to the attribute Key from Source when Source is non-empty.
+type TemplatePart struct {
+	Literal string
+	Source  string
+	Key     string
+}
+
+// AttributeTemplate is a compiled attribute template.
+type AttributeTemplate []TemplatePart
+
+// validTemplateSources is the set of attribute sources that templates can reference.
+var validTemplateSources = map[string]bool{
+	"resource": true,
+	"span":     true,
+	"event":    true,
+}
+
+// CompileAttributeTemplate compiles a template such as "{resource:service.name}:{event:request.id}"
+// into literal and attribute reference parts.
+func CompileAttributeTemplate(tmpl string) (AttributeTemplate, error) {
+	var parts AttributeTemplate
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			parts = append(parts, TemplatePart{Literal: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, TemplatePart{Literal: rest[:open]})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed reference in template %q", tmpl)
+		}
+		ref := rest[open+1 : open+end]
+		source, key, found := strings.Cut(ref, ":")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid reference %q in template %q, expected {source:key}", ref, tmpl)
+		}
+		if !validTemplateSources[source] {
+			return nil, fmt.Errorf("invalid reference source %q in template %q", source, tmpl)
+		}
+		parts = append(parts, TemplatePart{Source: source, Key: key})
+		rest = rest[open+end+1:]
+	}
+	return parts, nil
+}
@@ -5,7 +5,22 @@ package spaneventtologconnector // import "github.com/dev7a/otelcol-con-spaneven
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -16,10 +31,15 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/dev7a/otelcol-con-spaneventtolog/spaneventtologconnector/config"
+	"github.com/dev7a/otelcol-con-spaneventtolog/spaneventtologconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 )
 
 // severityMappings defines the canonical mapping between severity numbers and text.
@@ -79,6 +99,63 @@ var textToSeverityMap = func() map[string]plog.SeverityNumber {
 	return m
 }()
 
+// explicitSeverityNumberAttribute is the well-known event attribute holding an explicit severity
+// number, honored with the highest precedence when HonorExplicitSeverityNumberAttribute is set.
+const explicitSeverityNumberAttribute = "otel.log.severity_number"
+
+// log4jSeverityMap maps the Log4j/Logback levels to severity numbers. ALL enables every level and
+// maps to the most verbose severity; OFF ranks above FATAL and maps to the most severe one.
+var log4jSeverityMap = map[string]plog.SeverityNumber{
+	"all":   plog.SeverityNumberTrace,
+	"trace": plog.SeverityNumberTrace,
+	"debug": plog.SeverityNumberDebug,
+	"info":  plog.SeverityNumberInfo,
+	"warn":  plog.SeverityNumberWarn,
+	"error": plog.SeverityNumberError,
+	"fatal": plog.SeverityNumberFatal,
+	"off":   plog.SeverityNumberFatal4,
+}
+
+// semConvRenames maps each SemConvMode to the legacy event attribute keys it renames, and their
+// current OTel semantic-convention keys.
+var semConvRenames = map[string]map[string]string{
+	"http": {
+		"http.method":                  "http.request.method",
+		"http.status_code":             "http.response.status_code",
+		"http.url":                     "url.full",
+		"http.target":                  "url.path",
+		"http.scheme":                  "url.scheme",
+		"http.user_agent":              "user_agent.original",
+		"http.client_ip":               "client.address",
+		"http.request_content_length":  "http.request.body.size",
+		"http.response_content_length": "http.response.body.size",
+		"http.flavor":                  "network.protocol.version",
+	},
+	"db": {
+		"db.statement": "db.query.text",
+		"db.operation": "db.operation.name",
+		"db.name":      "db.namespace",
+		"db.sql.table": "db.collection.name",
+		"db.system":    "db.system.name",
+	},
+	"exception": {
+		"error.message":    "exception.message",
+		"error.stack":      "exception.stacktrace",
+		"error.stacktrace": "exception.stacktrace",
+		"error.kind":       "exception.type",
+	},
+}
+
+// Span flag bits describing whether the parent span context is remote, as defined by the OTLP
+// SpanFlags enum (SPAN_FLAGS_CONTEXT_HAS_IS_REMOTE_MASK and SPAN_FLAGS_CONTEXT_IS_REMOTE_MASK).
+const (
+	spanFlagsHasIsRemote uint32 = 0x100
+	spanFlagsIsRemote    uint32 = 0x200
+
+	// spanFlagsTraceFlagsMask selects the W3C trace flags held in the lower 8 bits of the span flags.
+	spanFlagsTraceFlagsMask uint32 = 0xff
+)
+
 // Connector is a span event to log connector.
 type Connector struct {
 	config       config.Config
@@ -86,18 +163,157 @@ type Connector struct {
 	logger       *zap.Logger
 	eventNameSet map[string]struct{}
 	tracer       trace.Tracer
+
+	// excludeEventNameSet is built from ExcludeEventNames.
+	excludeEventNameSet map[string]struct{}
+
+	meter metric.Meter
+
+	// truncatedAttributes counts the attributes removed to honor MaxTotalAttributes.
+	truncatedAttributes metric.Int64Counter
+
+	// heartbeats and heartbeatEventsProcessed are recorded periodically when HeartbeatMetric is set.
+	heartbeats               metric.Int64Counter
+	heartbeatEventsProcessed metric.Int64Gauge
+
+	// eventsProcessed is the cumulative number of events converted to logs, reported by the heartbeat.
+	eventsProcessed atomic.Int64
+
+	// emissionSequence numbers the emitted records across ConsumeTraces calls when IncludeEmissionSequence is set.
+	emissionSequence atomic.Int64
+
+	// heartbeatStop and heartbeatDone stop the heartbeat goroutine and wait for it to exit.
+	heartbeatStop chan struct{}
+	heartbeatDone chan struct{}
+
+	// now returns the current time. It can be replaced in tests.
+	now func() time.Time
+
+	// includeEventPatterns and excludeEventPatterns are compiled from IncludeEventNamePatterns,
+	// split by whether the pattern is negated.
+	includeEventPatterns []*regexp.Regexp
+	excludeEventPatterns []*regexp.Regexp
+
+	// spanKindSet is built from IncludeSpanKinds.
+	spanKindSet map[ptrace.SpanKind]struct{}
+
+	// spanStatusSet is built from IncludeSpanStatuses.
+	spanStatusSet map[ptrace.StatusCode]struct{}
+
+	// spanNamePatterns are compiled from SpanNamePatterns.
+	spanNamePatterns []*regexp.Regexp
+
+	// skipSpanContextPatterns are compiled from SkipSpanContextForPatterns.
+	skipSpanContextPatterns []*regexp.Regexp
+
+	// routingTemplates are compiled from RoutingAttributes.
+	routingTemplates map[string]config.AttributeTemplate
+
+	// correlationKeyTemplate is compiled from CorrelationKey.Template.
+	correlationKeyTemplate config.AttributeTemplate
+
+	// downgradeSeverities is built from DowngradeSeverities.
+	downgradeSeverities map[plog.SeverityNumber]plog.SeverityNumber
+
+	// severityAliases is built from SeverityAliases, keyed by the lowercased alias.
+	severityAliases map[string]plog.SeverityNumber
+
+	// bodyExtractPattern is compiled from BodyExtractPattern.
+	bodyExtractPattern *regexp.Regexp
+
+	// eventAttributeFilter, spanAttributeFilter and resourceAttributeFilter select the attributes
+	// copied from each source.
+	eventAttributeFilter    attributeFilter
+	spanAttributeFilter     attributeFilter
+	resourceAttributeFilter attributeFilter
+
+	// componentID identifies this connector instance on the marker records of InjectConnectorScope.
+	componentID string
+
+	// collectorHostname is resolved once in Start when IncludeCollectorHostname is set.
+	collectorHostname string
+
+	// filterConditions is compiled from FilterConditions, nil when unset.
+	filterConditions *ottl.ConditionSequence[ottlspanevent.TransformContext]
+
+	// transformStatements is compiled from TransformStatements, nil when unset.
+	transformStatements *ottl.StatementSequence[ottllog.TransformContext]
+
+	// minEventTime and maxEventTime are parsed from MinEventTime and MaxEventTime, zero when unset.
+	minEventTime time.Time
+	maxEventTime time.Time
+
+	// staticResource is built from StaticResource, and used for all output if hasStaticResource is set.
+	staticResource     pcommon.Resource
+	staticResourceHash uint64
+	hasStaticResource  bool
+
+	// renameRules are compiled from AttributeRenameRules, in order.
+	renameRules []attributeRenameRule
+
+	// numericThresholdRules are the SeverityByNumericThreshold rules sorted by descending threshold.
+	numericThresholdRules []config.NumericThresholdRule
+
+	// severityResolver is an optional custom resolver consulted before the configured severity sources.
+	severityResolver SeverityResolver
+}
+
+// SeverityResolver resolves the severity of a span event. Embedders can inject a custom resolver
+// with WithSeverityResolver to implement severity logic not covered by the configuration.
+type SeverityResolver interface {
+	// Resolve returns the severity number and text for the event, and whether it resolved one.
+	// If false is returned, the configured severity sources are consulted as usual.
+	Resolve(event ptrace.SpanEvent, span ptrace.Span) (plog.SeverityNumber, string, bool)
+}
+
+// attributeRenameRule is a compiled AttributeRenameRule.
+type attributeRenameRule struct {
+	pattern     *regexp.Regexp
+	replacement string
 }
 
 var _ consumer.Traces = (*Connector)(nil)
 var _ component.Component = (*Connector)(nil)
 
 // newConnector creates a new span event to log connector.
-func newConnector(settings connector.Settings, cfg config.Config, logsConsumer consumer.Logs) *Connector {
+func newConnector(settings connector.Settings, cfg config.Config, logsConsumer consumer.Logs) (*Connector, error) {
 	c := &Connector{
 		config:       cfg,
 		logsConsumer: logsConsumer,
 		logger:       settings.Logger,
 		tracer:       settings.TracerProvider.Tracer(settings.ID.String()),
+		meter:        settings.MeterProvider.Meter(settings.ID.String()),
+		now:          time.Now,
+		componentID:  settings.ID.String(),
+	}
+
+	truncatedAttributes, err := c.meter.Int64Counter(
+		"spaneventtolog.truncated_attributes",
+		metric.WithDescription("Number of log record attributes removed to honor max_total_attributes."),
+		metric.WithUnit("{attribute}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create truncated attributes counter: %w", err)
+	}
+	c.truncatedAttributes = truncatedAttributes
+
+	if cfg.HeartbeatMetric {
+		c.heartbeats, err = c.meter.Int64Counter(
+			"spaneventtolog.heartbeats",
+			metric.WithDescription("Number of heartbeats recorded while the connector is running."),
+			metric.WithUnit("{heartbeat}"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create heartbeat counter: %w", err)
+		}
+		c.heartbeatEventsProcessed, err = c.meter.Int64Gauge(
+			"spaneventtolog.heartbeat.events_processed",
+			metric.WithDescription("Cumulative number of span events converted to logs, reported by the heartbeat."),
+			metric.WithUnit("{event}"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create heartbeat events processed gauge: %w", err)
+		}
 	}
 
 	// Create a map for fast lookup of included event names
@@ -108,7 +324,187 @@ func newConnector(settings connector.Settings, cfg config.Config, logsConsumer c
 		}
 	}
 
-	return c
+	// Create a map for fast lookup of excluded event names
+	if len(cfg.ExcludeEventNames) > 0 {
+		c.excludeEventNameSet = make(map[string]struct{}, len(cfg.ExcludeEventNames))
+		for _, name := range cfg.ExcludeEventNames {
+			c.excludeEventNameSet[name] = struct{}{}
+		}
+	}
+
+	// Compile event name patterns, separating negations from inclusions
+	for _, pattern := range cfg.IncludeEventNamePatterns {
+		negated := strings.HasPrefix(pattern, "!")
+		re, err := config.CompileEventNamePattern(strings.TrimPrefix(pattern, "!"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid include event name pattern %q: %w", pattern, err)
+		}
+		if negated {
+			c.excludeEventPatterns = append(c.excludeEventPatterns, re)
+		} else {
+			c.includeEventPatterns = append(c.includeEventPatterns, re)
+		}
+	}
+
+	// Create a map for fast lookup of included span kinds
+	if len(cfg.IncludeSpanKinds) > 0 {
+		c.spanKindSet = make(map[ptrace.SpanKind]struct{}, len(cfg.IncludeSpanKinds))
+		for _, kind := range cfg.IncludeSpanKinds {
+			spanKind, ok := parseSpanKind(kind)
+			if !ok {
+				return nil, fmt.Errorf("invalid span kind: %s", kind)
+			}
+			c.spanKindSet[spanKind] = struct{}{}
+		}
+	}
+
+	// Create a map for fast lookup of included span statuses
+	if len(cfg.IncludeSpanStatuses) > 0 {
+		c.spanStatusSet = make(map[ptrace.StatusCode]struct{}, len(cfg.IncludeSpanStatuses))
+		for _, status := range cfg.IncludeSpanStatuses {
+			statusCode, ok := parseStatusCode(status)
+			if !ok {
+				return nil, fmt.Errorf("invalid span status: %s", status)
+			}
+			c.spanStatusSet[statusCode] = struct{}{}
+		}
+	}
+
+	// Compile span name patterns
+	for _, pattern := range cfg.SpanNamePatterns {
+		re, err := config.CompileEventNamePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid span name pattern %q: %w", pattern, err)
+		}
+		c.spanNamePatterns = append(c.spanNamePatterns, re)
+	}
+
+	// Map each severity alias to the severity number of its canonical target
+	if len(cfg.SeverityAliases) > 0 {
+		c.severityAliases = make(map[string]plog.SeverityNumber, len(cfg.SeverityAliases))
+		for alias, target := range cfg.SeverityAliases {
+			severityNumber, _ := mapSeverity(target)
+			if severityNumber == plog.SeverityNumberUnspecified {
+				return nil, fmt.Errorf("invalid severity alias target for %s: %s", alias, target)
+			}
+			c.severityAliases[strings.ToLower(alias)] = severityNumber
+		}
+	}
+
+	// Map each downgraded severity number to its target
+	if len(cfg.DowngradeSeverities) > 0 {
+		c.downgradeSeverities = make(map[plog.SeverityNumber]plog.SeverityNumber, len(cfg.DowngradeSeverities))
+		for severity, target := range cfg.DowngradeSeverities {
+			severityNumber, _ := c.mapSeverity(severity)
+			targetNumber, _ := c.mapSeverity(target)
+			if severityNumber == plog.SeverityNumberUnspecified || targetNumber == plog.SeverityNumberUnspecified {
+				return nil, fmt.Errorf("invalid severity downgrade from %s to %s", severity, target)
+			}
+			c.downgradeSeverities[severityNumber] = targetNumber
+		}
+	}
+
+	// Compile span context skip patterns
+	for _, pattern := range cfg.SkipSpanContextForPatterns {
+		re, err := config.CompileEventNamePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid skip span context pattern %q: %w", pattern, err)
+		}
+		c.skipSpanContextPatterns = append(c.skipSpanContextPatterns, re)
+	}
+
+	// Compile routing attribute templates
+	if len(cfg.RoutingAttributes) > 0 {
+		c.routingTemplates = make(map[string]config.AttributeTemplate, len(cfg.RoutingAttributes))
+		for key, tmpl := range cfg.RoutingAttributes {
+			compiled, err := config.CompileAttributeTemplate(tmpl)
+			if err != nil {
+				return nil, fmt.Errorf("invalid routing attribute template for %s: %w", key, err)
+			}
+			c.routingTemplates[key] = compiled
+		}
+	}
+
+	// Sort numeric threshold rules so the highest threshold is evaluated first
+	if len(cfg.SeverityByNumericThreshold.Rules) > 0 {
+		c.numericThresholdRules = append([]config.NumericThresholdRule(nil), cfg.SeverityByNumericThreshold.Rules...)
+		sort.SliceStable(c.numericThresholdRules, func(i, j int) bool {
+			return c.numericThresholdRules[i].Threshold > c.numericThresholdRules[j].Threshold
+		})
+	}
+
+	// Compile the correlation key template
+	if cfg.CorrelationKey.Template != "" {
+		compiled, err := config.CompileAttributeTemplate(cfg.CorrelationKey.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid correlation key template: %w", err)
+		}
+		c.correlationKeyTemplate = compiled
+	}
+
+	// Build the attribute filters of each copied source
+	c.eventAttributeFilter = newAttributeFilter(cfg.EventAttributeAllowlist, cfg.EventAttributeDenylist)
+	c.spanAttributeFilter = newAttributeFilter(cfg.SpanAttributeAllowlist, cfg.SpanAttributeDenylist)
+	c.resourceAttributeFilter = newAttributeFilter(cfg.ResourceAttributeAllowlist, cfg.ResourceAttributeDenylist)
+
+	// Compile the OTTL filter conditions, all of which must pass
+	if len(cfg.FilterConditions) > 0 {
+		conditions, err := config.ParseFilterConditions(cfg.FilterConditions, settings.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		sequence := ottl.NewConditionSequence(conditions, settings.TelemetrySettings,
+			ottl.WithLogicOperation[ottlspanevent.TransformContext](ottl.And))
+		c.filterConditions = &sequence
+	}
+
+	// Compile the OTTL transform statements, logging and skipping those failing at runtime
+	if len(cfg.TransformStatements) > 0 {
+		statements, err := config.ParseTransformStatements(cfg.TransformStatements, settings.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		sequence := ottl.NewStatementSequence(statements, settings.TelemetrySettings,
+			ottl.WithStatementSequenceErrorMode[ottllog.TransformContext](ottl.IgnoreError))
+		c.transformStatements = &sequence
+	}
+
+	// Parse the event time window
+	minEventTime, maxEventTime, err := cfg.EventTimeWindow()
+	if err != nil {
+		return nil, err
+	}
+	c.minEventTime, c.maxEventTime = minEventTime, maxEventTime
+
+	// Build the synthetic resource replacing the source resources
+	if len(cfg.StaticResource) > 0 {
+		c.staticResource = pcommon.NewResource()
+		for key, value := range cfg.StaticResource {
+			c.staticResource.Attributes().PutStr(key, value)
+		}
+		c.staticResourceHash = hashResource(c.staticResource)
+		c.hasStaticResource = true
+	}
+
+	// Compile the body extraction pattern
+	if cfg.BodyExtractPattern != "" {
+		re, err := regexp.Compile(cfg.BodyExtractPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body extract pattern %q: %w", cfg.BodyExtractPattern, err)
+		}
+		c.bodyExtractPattern = re
+	}
+
+	// Compile attribute rename rules
+	for _, rule := range cfg.AttributeRenameRules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute rename pattern %q: %w", rule.Pattern, err)
+		}
+		c.renameRules = append(c.renameRules, attributeRenameRule{pattern: re, replacement: rule.Replacement})
+	}
+
+	return c, nil
 }
 
 // Capabilities implements the consumer interface.
@@ -126,281 +522,2182 @@ func (c *Connector) ConsumeTraces(ctx context.Context, traces ptrace.Traces) err
 	)
 	defer span.End()
 
-	logs := c.extractLogsFromTraces(ctx, traces)
+	logs := c.ExtractLogs(ctx, traces)
+	span.SetAttributes(attribute.Int("output_logs", logs.LogRecordCount()))
+
+	// Send error records ahead of the rest so that they aren't delayed behind large batches
+	if c.config.FlushErrorsImmediately {
+		errorLogs := splitErrorLogs(logs)
+		if errorLogs.LogRecordCount() > 0 {
+			span.SetAttributes(attribute.Int("output_error_logs", errorLogs.LogRecordCount()))
+			if err := c.consumeLogs(ctx, span, errorLogs); err != nil {
+				return err
+			}
+		}
+	}
 
 	if logs.LogRecordCount() > 0 {
-		span.SetAttributes(attribute.Int("output_logs", logs.LogRecordCount()))
-		err := c.logsConsumer.ConsumeLogs(ctx, logs)
+		return c.consumeLogs(ctx, span, logs)
+	}
+
+	return nil
+}
+
+// consumeLogs sends logs to the next consumer, recording any error on the given span.
+func (c *Connector) consumeLogs(ctx context.Context, span trace.Span, logs plog.Logs) error {
+	err := c.logsConsumer.ConsumeLogs(ctx, logs)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// splitErrorLogs moves the error and fatal records of logs into a new plog.Logs, keeping their
+// resources and scopes. ResourceLogs and ScopeLogs left without records are removed from logs, and
+// converted event counts on the resources are split along with their records.
+func splitErrorLogs(logs plog.Logs) plog.Logs {
+	errorLogs := plog.NewLogs()
+	logs.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		var errorRl plog.ResourceLogs
+		hasErrorRl := false
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			var errorSl plog.ScopeLogs
+			hasErrorSl := false
+			sl.LogRecords().RemoveIf(func(logRecord plog.LogRecord) bool {
+				if logRecord.SeverityNumber() < plog.SeverityNumberError {
+					return false
+				}
+				if !hasErrorRl {
+					errorRl = errorLogs.ResourceLogs().AppendEmpty()
+					rl.Resource().CopyTo(errorRl.Resource())
+					errorRl.SetSchemaUrl(rl.SchemaUrl())
+					hasErrorRl = true
+				}
+				if !hasErrorSl {
+					errorSl = errorRl.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(errorSl.Scope())
+					errorSl.SetSchemaUrl(sl.SchemaUrl())
+					hasErrorSl = true
+				}
+				logRecord.MoveTo(errorSl.LogRecords().AppendEmpty())
+				return true
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		if count, exists := rl.Resource().Attributes().Get("spaneventtolog.converted_events"); exists && hasErrorRl {
+			moved := int64(0)
+			for i := 0; i < errorRl.ScopeLogs().Len(); i++ {
+				moved += int64(errorRl.ScopeLogs().At(i).LogRecords().Len())
+			}
+			count.SetInt(count.Int() - moved)
+			errorRl.Resource().Attributes().PutInt("spaneventtolog.converted_events", moved)
+		}
+		return rl.ScopeLogs().Len() == 0
+	})
+	return errorLogs
+}
+
+// Start implements the component.Component interface.
+func (c *Connector) Start(_ context.Context, _ component.Host) error {
+	if c.config.IncludeCollectorHostname {
+		hostname, err := os.Hostname()
 		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return err
+			c.logger.Warn("Failed to resolve the collector hostname", zap.Error(err))
 		}
-	} else {
-		span.SetAttributes(attribute.Int("output_logs", 0))
+		c.collectorHostname = hostname
+	}
+	if c.config.HeartbeatMetric {
+		c.heartbeatStop = make(chan struct{})
+		c.heartbeatDone = make(chan struct{})
+		go c.runHeartbeat(c.heartbeatStop, c.heartbeatDone)
+	}
+	return nil
+}
+
+// Shutdown implements the component.Component interface.
+func (c *Connector) Shutdown(_ context.Context) error {
+	if c.heartbeatStop != nil {
+		close(c.heartbeatStop)
+		<-c.heartbeatDone
+		c.heartbeatStop = nil
+	}
+	return nil
+}
+
+// runHeartbeat records the heartbeat metrics every HeartbeatInterval until stop is closed.
+func (c *Connector) runHeartbeat(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.heartbeats.Add(ctx, 1)
+			c.heartbeatEventsProcessed.Record(ctx, c.eventsProcessed.Load())
+		}
+	}
+}
+
+// attributeFilter selects the attributes copied from a source by key.
+type attributeFilter struct {
+	// allow is the set of keys to copy; if nil, every key is copied.
+	allow map[string]struct{}
+
+	// deny is the set of keys never copied, taking precedence over allow.
+	deny map[string]struct{}
+}
+
+// newAttributeFilter creates a filter copying only the allowed keys, or every key if none are
+// listed, except the denied keys.
+func newAttributeFilter(allow, deny []string) attributeFilter {
+	return attributeFilter{allow: keySet(allow), deny: keySet(deny)}
+}
+
+// keySet returns the set of the given keys, or nil if there are none.
+func keySet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
+
+// permits determines if the attribute with the given key should be copied.
+func (f attributeFilter) permits(key string) bool {
+	if f.denies(key) {
+		return false
+	}
+	if f.allow == nil {
+		return true
+	}
+	_, allowed := f.allow[key]
+	return allowed
+}
+
+// denies determines if the given key is denied.
+func (f attributeFilter) denies(key string) bool {
+	_, denied := f.deny[key]
+	return denied
+}
+
+// copyUndeniedAttributes copies the attributes of src into dst verbatim, leaving out the keys denied
+// by filter. Used wherever event attributes are copied other than as log record attributes.
+func copyUndeniedAttributes(dst, src pcommon.Map, filter attributeFilter) {
+	src.CopyTo(dst)
+	if filter.deny != nil {
+		dst.RemoveIf(func(k string, _ pcommon.Value) bool {
+			return filter.denies(k)
+		})
+	}
+}
+
+// resourceLogsIndex groups the ResourceLogs created during one extraction by the content of their
+// source resource, so that logically-equal resources share a single ResourceLogs.
+type resourceLogsIndex struct {
+	logs   plog.Logs
+	groups map[uint64][]resourceGroup
+}
+
+// resourceGroup is a ResourceLogs along with the source resource and schema URL it was created for.
+type resourceGroup struct {
+	source       pcommon.Resource
+	schemaURL    string
+	resourceLogs plog.ResourceLogs
+}
+
+// newResourceLogsIndex creates an index of the ResourceLogs created in logs from now on.
+func newResourceLogsIndex(logs plog.Logs) *resourceLogsIndex {
+	return &resourceLogsIndex{logs: logs, groups: make(map[uint64][]resourceGroup)}
+}
+
+// findOrCreate finds the ResourceLogs created for a resource equal to res with the same schema URL,
+// or creates a new one. hash must be the hashResource value of res. Returns the ResourceLogs and a
+// boolean indicating if it was newly created.
+func (idx *resourceLogsIndex) findOrCreate(res pcommon.Resource, schemaURL string, hash uint64) (plog.ResourceLogs, bool) {
+	for _, group := range idx.groups[hash] {
+		if group.schemaURL == schemaURL && sameResource(group.source, res) {
+			return group.resourceLogs, false
+		}
+	}
+	newRl := idx.logs.ResourceLogs().AppendEmpty()
+	res.CopyTo(newRl.Resource())
+	newRl.SetSchemaUrl(schemaURL)
+	idx.groups[hash] = append(idx.groups[hash], resourceGroup{source: res, schemaURL: schemaURL, resourceLogs: newRl})
+	return newRl, true
+}
+
+// hashResource returns the 64-bit FNV-1a hash of the resource attributes, sorted by key. Values are
+// hashed along with their type, so that e.g. an int 1 and a string "1" differ.
+func hashResource(res pcommon.Resource) uint64 {
+	h := fnv.New64a()
+	writeAttributesHash(h, res.Attributes())
+	return h.Sum64()
+}
+
+// writeAttributesHash writes the attributes, sorted by key, to the hash.
+func writeAttributesHash(h hash.Hash64, attrs pcommon.Map) {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write([]byte{0, byte(v.Type())})
+		_, _ = h.Write([]byte(v.AsString()))
+		_, _ = h.Write([]byte{0})
+	}
+}
+
+// sameResource determines if two resources have the same attributes and dropped attributes count.
+func sameResource(a, b pcommon.Resource) bool {
+	return a.DroppedAttributesCount() == b.DroppedAttributesCount() &&
+		sameAttributes(a.Attributes(), b.Attributes())
+}
+
+// sameScope determines if two instrumentation scopes have the same name, version, attributes and
+// dropped attributes count.
+func sameScope(a, b pcommon.InstrumentationScope) bool {
+	return a.Name() == b.Name() &&
+		a.Version() == b.Version() &&
+		a.DroppedAttributesCount() == b.DroppedAttributesCount() &&
+		sameAttributes(a.Attributes(), b.Attributes())
+}
+
+// sameAttributes determines if two attribute maps hold the same keys and values.
+func sameAttributes(a, b pcommon.Map) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	if a.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.AsRaw(), b.AsRaw())
+}
+
+// dedupeLogRecords removes the log records with the same body, severity and attributes as an earlier
+// record from the ResourceLogs created from the given index on, along with the ScopeLogs and
+// ResourceLogs left empty. Attributes with the ignored keys are left out of the comparison.
+// Returns the number of records removed.
+func dedupeLogRecords(logs plog.Logs, firstResourceLogs int, ignored map[string]struct{}) int {
+	seen := make(map[uint64][]dedupeEntry)
+	removed := 0
+	rls := logs.ResourceLogs()
+	for i := firstResourceLogs; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sls.At(j).LogRecords().RemoveIf(func(logRecord plog.LogRecord) bool {
+				entry := dedupeEntry{logRecord, dedupeAttributes(logRecord.Attributes(), ignored)}
+				hash := entry.hash()
+				for _, other := range seen[hash] {
+					if other.same(entry) {
+						removed++
+						return true
+					}
+				}
+				seen[hash] = append(seen[hash], entry)
+				return false
+			})
+		}
+		sls.RemoveIf(func(sl plog.ScopeLogs) bool {
+			return sl.LogRecords().Len() == 0
+		})
+	}
+
+	index := 0
+	rls.RemoveIf(func(rl plog.ResourceLogs) bool {
+		index++
+		return index > firstResourceLogs && rl.ScopeLogs().Len() == 0
+	})
+	return removed
+}
+
+// dedupeEntry is a log record along with the attributes compared when deduplicating it.
+type dedupeEntry struct {
+	logRecord  plog.LogRecord
+	attributes pcommon.Map
+}
+
+// dedupeAttributes returns attrs without the ignored keys, copying them only if any is present.
+func dedupeAttributes(attrs pcommon.Map, ignored map[string]struct{}) pcommon.Map {
+	hasIgnored := false
+	for key := range ignored {
+		if _, exists := attrs.Get(key); exists {
+			hasIgnored = true
+			break
+		}
+	}
+	if !hasIgnored {
+		return attrs
+	}
+	compared := pcommon.NewMap()
+	attrs.CopyTo(compared)
+	compared.RemoveIf(func(k string, _ pcommon.Value) bool {
+		_, isIgnored := ignored[k]
+		return isIgnored
+	})
+	return compared
+}
+
+// hash returns the 64-bit FNV-1a hash of the body, severity and compared attributes of the entry.
+func (e dedupeEntry) hash() uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte{byte(e.logRecord.Body().Type()), byte(e.logRecord.SeverityNumber())})
+	_, _ = h.Write([]byte(e.logRecord.Body().AsString()))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(e.logRecord.SeverityText()))
+	_, _ = h.Write([]byte{0})
+	writeAttributesHash(h, e.attributes)
+	return h.Sum64()
+}
+
+// same determines if two entries have the same body, severity and compared attributes.
+func (e dedupeEntry) same(other dedupeEntry) bool {
+	a, b := e.logRecord, other.logRecord
+	return a.SeverityNumber() == b.SeverityNumber() &&
+		a.SeverityText() == b.SeverityText() &&
+		a.Body().Type() == b.Body().Type() &&
+		reflect.DeepEqual(a.Body().AsRaw(), b.Body().AsRaw()) &&
+		sameAttributes(e.attributes, other.attributes)
+}
+
+// recordUniqueAttributes returns the keys of the configured attributes that differ for every log
+// record, such as sequence numbers and record IDs, which deduplication doesn't compare.
+func (c *Connector) recordUniqueAttributes() map[string]struct{} {
+	keys := map[string]struct{}{}
+	if c.config.SequenceAttribute != "" {
+		keys[c.config.SequenceAttribute] = struct{}{}
+	}
+	if c.config.IncludeEmissionSequence {
+		keys["spaneventtolog.emission_seq"] = struct{}{}
+	}
+	if c.config.GenerateRecordID {
+		keys["log.record.id"] = struct{}{}
+	}
+	if c.config.AnnotateSourceIndex {
+		keys["spaneventtolog.source_resource_index"] = struct{}{}
+		keys["spaneventtolog.source_span_index"] = struct{}{}
+	}
+	return keys
+}
+
+// findOrCreateScopeLogs finds existing ScopeLogs for a scope equal to the given one with the same
+// schema URL, or creates a new one within ResourceLogs. Returns the ScopeLogs.
+func findOrCreateScopeLogs(rl plog.ResourceLogs, scope pcommon.InstrumentationScope, schemaURL string) plog.ScopeLogs {
+	sls := rl.ScopeLogs()
+	for i := 0; i < sls.Len(); i++ {
+		sl := sls.At(i)
+		if sl.SchemaUrl() == schemaURL && sameScope(sl.Scope(), scope) {
+			return sl
+		}
+	}
+	newSl := sls.AppendEmpty()
+	scope.CopyTo(newSl.Scope())
+	newSl.SetSchemaUrl(schemaURL)
+	return newSl
+}
+
+// findOrCreateNamedScopeLogs finds existing ScopeLogs with the given scope name and version or creates
+// a new one within ResourceLogs. Returns the ScopeLogs.
+func findOrCreateNamedScopeLogs(rl plog.ResourceLogs, name, version string) plog.ScopeLogs {
+	sls := rl.ScopeLogs()
+	for i := 0; i < sls.Len(); i++ {
+		sl := sls.At(i)
+		if sl.Scope().Name() == name && sl.Scope().Version() == version {
+			return sl
+		}
+	}
+	newSl := sls.AppendEmpty()
+	newSl.Scope().SetName(name)
+	newSl.Scope().SetVersion(version)
+	return newSl
+}
+
+// findOrCreateResourceLogs finds or creates the ResourceLogs for a source resource, copying the
+// resource attributes only if configured and only when the ResourceLogs is first created. If a
+// static resource is configured, its single ResourceLogs is returned for every source resource.
+func (c *Connector) findOrCreateResourceLogs(index *resourceLogsIndex, resource pcommon.Resource, schemaURL string, hash uint64) plog.ResourceLogs {
+	if c.hasStaticResource {
+		resourceLogs, _ := index.findOrCreate(c.staticResource, "", c.staticResourceHash)
+		return resourceLogs
+	}
+
+	resourceLogs, createdRl := index.findOrCreate(resource, schemaURL, hash)
+	if createdRl {
+		if c.shouldCopyAttributes("resource.attributes") {
+			resource.Attributes().CopyTo(resourceLogs.Resource().Attributes())
+			resourceLogs.Resource().Attributes().RemoveIf(func(k string, _ pcommon.Value) bool {
+				return !c.resourceAttributeFilter.permits(k)
+			})
+		} else {
+			// Ensure resourceLogs has a resource object, even if empty
+			resourceLogs.Resource().Attributes().Clear()
+		}
+	}
+	return resourceLogs
+}
+
+// scopeVersionFromAttribute returns the output scope version taken from the OutputScopeVersionFromAttribute
+// attribute, looked up on the event first and then on the resource. Returns false if neither carries it.
+func (c *Connector) scopeVersionFromAttribute(event ptrace.SpanEvent, resource pcommon.Resource) (string, bool) {
+	if c.config.OutputScopeVersionFromAttribute == "" {
+		return "", false
 	}
+	if v, exists := event.Attributes().Get(c.config.OutputScopeVersionFromAttribute); exists {
+		return v.AsString(), true
+	}
+	if v, exists := resource.Attributes().Get(c.config.OutputScopeVersionFromAttribute); exists {
+		return v.AsString(), true
+	}
+	return "", false
+}
+
+// scopeNameFromAttribute returns the output scope name taken from the ScopeByAttribute attribute,
+// looked up on the event first and then on the resource. Returns false if neither carries it.
+func (c *Connector) scopeNameFromAttribute(event ptrace.SpanEvent, resource pcommon.Resource) (string, bool) {
+	if c.config.ScopeByAttribute == "" {
+		return "", false
+	}
+	if v, exists := event.Attributes().Get(c.config.ScopeByAttribute); exists {
+		return v.AsString(), true
+	}
+	if v, exists := resource.Attributes().Get(c.config.ScopeByAttribute); exists {
+		return v.AsString(), true
+	}
+	return "", false
+}
+
+// findErrorTraces returns the IDs of the traces with at least one span whose status is Error.
+func findErrorTraces(traces ptrace.Traces) map[pcommon.TraceID]struct{} {
+	errorTraces := make(map[pcommon.TraceID]struct{})
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if spans.At(k).Status().Code() == ptrace.StatusCodeError {
+					errorTraces[spans.At(k).TraceID()] = struct{}{}
+				}
+			}
+		}
+	}
+	return errorTraces
+}
+
+// ExtractLogs converts the span events of the traces into a new plog.Logs.
+func (c *Connector) ExtractLogs(ctx context.Context, traces ptrace.Traces) plog.Logs {
+	logs := plog.NewLogs()
+	c.AppendLogs(ctx, traces, logs)
+	return logs
+}
+
+// AppendLogs converts the span events of the traces and appends the resulting records to dst,
+// letting embedders that already hold a plog.Logs avoid allocating a new one. Existing resource
+// logs in dst are left untouched.
+func (c *Connector) AppendLogs(ctx context.Context, traces ptrace.Traces, dst plog.Logs) {
+	c.extractLogsFromTraces(ctx, traces, dst)
+}
+
+// batchState holds information about the traces batch being converted, computed once per batch.
+type batchState struct {
+	// errorTraces is the set of trace IDs containing error spans, or nil if not filtering by errors.
+	errorTraces map[pcommon.TraceID]struct{}
+
+	// spanCount is the total number of spans in the batch.
+	spanCount int
+
+	// attributeBudget bounds the attributes of the batch's log records, or is nil if unbounded.
+	attributeBudget *attributeBudget
+}
+
+// spanLogRecord is a log record created from a span, with the ScopeLogs and ResourceLogs it was
+// appended to and the number of its attributes already charged against the attribute budget.
+type spanLogRecord struct {
+	logRecord    plog.LogRecord
+	scopeLogs    plog.ScopeLogs
+	resourceLogs plog.ResourceLogs
+	charged      int
+}
+
+// attributeBudget bounds the number of attributes held by the log records of a batch. A nil
+// budget is unbounded.
+type attributeBudget struct {
+	// remaining is the number of attributes the batch's records may still hold.
+	remaining int
+
+	// pending is the number of attributes skipped while populating the current log record.
+	pending int
+
+	// truncated is the number of attributes skipped or removed across the batch.
+	truncated int
+}
+
+// newAttributeBudget returns a budget of limit attributes, or nil if limit is not positive.
+func newAttributeBudget(limit int) *attributeBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &attributeBudget{remaining: limit}
+}
+
+// allows determines if key may be put in attrs, the attributes of the log record being populated.
+// A refused attribute is counted against the record when the budget is enforced.
+func (b *attributeBudget) allows(attrs pcommon.Map, key string) bool {
+	if b == nil || attrs.Len() < b.remaining {
+		return true
+	}
+	if _, exists := attrs.Get(key); exists {
+		return true
+	}
+	b.pending++
+	return false
+}
+
+// enforce removes the attributes of a log record beyond the charged ones and the remaining budget,
+// and charges the ones kept against it. Attributes skipped or removed are added to the record's
+// dropped count. Returns the number of attributes now charged for the record.
+func (b *attributeBudget) enforce(logRecord plog.LogRecord, charged int) int {
+	if b == nil {
+		return 0
+	}
+	removed := truncateAttributes(logRecord.Attributes(), charged+b.remaining) + b.pending
+	b.remaining -= logRecord.Attributes().Len() - charged
+	b.pending = 0
+	if removed > 0 {
+		logRecord.SetDroppedAttributesCount(logRecord.DroppedAttributesCount() + uint32(removed))
+		b.truncated += removed
+	}
+	return logRecord.Attributes().Len()
+}
+
+// take charges one value added to an already enforced log record against the budget. Returns false,
+// adding it to the record's dropped count, if the budget is exhausted.
+func (b *attributeBudget) take(logRecord plog.LogRecord) bool {
+	if b == nil {
+		return true
+	}
+	if b.remaining > 0 {
+		b.remaining--
+		return true
+	}
+	logRecord.SetDroppedAttributesCount(logRecord.DroppedAttributesCount() + 1)
+	b.truncated++
+	return false
+}
+
+// extractLogsFromTraces extracts logs from traces into logs, grouping by resource and scope.
+// Resource logs already present in logs are left untouched.
+func (c *Connector) extractLogsFromTraces(ctx context.Context, traces ptrace.Traces, logs plog.Logs) {
+	ctx, otelSpan := c.tracer.Start(ctx, "connector/spaneventtolog/ExtractLogs")
+	defer otelSpan.End()
+
+	if traces.ResourceSpans().Len() == 0 {
+		otelSpan.SetAttributes(attribute.String("result", "no_resource_spans"))
+		return
+	}
+
+	batch := batchState{
+		spanCount:       traces.SpanCount(),
+		attributeBudget: newAttributeBudget(c.config.MaxTotalAttributes),
+	}
+
+	// Pre-scan the batch for traces containing errors if configured
+	if c.config.ErrorTracesOnly {
+		batch.errorTraces = findErrorTraces(traces)
+		otelSpan.SetAttributes(attribute.Int("error_traces", len(batch.errorTraces)))
+	}
+
+	firstResourceLogs := logs.ResourceLogs().Len()
+	existingRecords := logs.LogRecordCount()
+
+	totalEvents := 0
+	processedEvents := 0
+
+	// Tally why events were dropped for the detailed span attributes if configured
+	droppedEvents := map[string]int{}
+
+	// Number emitted records in processing order, per batch and across batches, if configured
+	var sequence int64
+	stampSequence := func(logRecord plog.LogRecord) {
+		if c.config.SequenceAttribute != "" {
+			logRecord.Attributes().PutInt(c.config.SequenceAttribute, sequence)
+			sequence++
+		}
+		if c.config.IncludeEmissionSequence {
+			logRecord.Attributes().PutInt("spaneventtolog.emission_seq", c.emissionSequence.Add(1)-1)
+		}
+	}
+
+	// Finish a populated record: transform it, number it, then bound the attributes it gained
+	finishLogRecord := func(record spanLogRecord) {
+		c.transformLogRecord(ctx, record.logRecord, record.scopeLogs, record.resourceLogs)
+		stampSequence(record.logRecord)
+		batch.attributeBudget.enforce(record.logRecord, record.charged)
+	}
+
+	// Only the ResourceLogs created here are considered for grouping, leaving existing ones untouched
+	resourceLogsIndex := newResourceLogsIndex(logs)
+
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		resourceSpans := traces.ResourceSpans().At(i)
+		resource := resourceSpans.Resource()
+
+		// Hash the resource once, and resolve its ResourceLogs on first use
+		resourceHash := hashResource(resource)
+		var resourceLogs plog.ResourceLogs
+		hasResourceLogs := false
+		getResourceLogs := func() plog.ResourceLogs {
+			if !hasResourceLogs {
+				resourceLogs = c.findOrCreateResourceLogs(resourceLogsIndex, resource, resourceSpans.SchemaUrl(), resourceHash)
+				hasResourceLogs = true
+			}
+			return resourceLogs
+		}
+
+		for j := 0; j < resourceSpans.ScopeSpans().Len(); j++ {
+			scopeSpans := resourceSpans.ScopeSpans().At(j)
+			scope := scopeSpans.Scope()
+
+			for k := 0; k < scopeSpans.Spans().Len(); k++ {
+				span := scopeSpans.Spans().At(k)
+
+				// Skip spans from traces without errors if we're only converting error traces
+				if batch.errorTraces != nil {
+					if _, exists := batch.errorTraces[span.TraceID()]; !exists {
+						droppedEvents["error_traces"] += span.Events().Len()
+						continue
+					}
+				}
+
+				// Skip spans that don't pass the span filters, before looking at their events
+				if !c.includeSpan(span) {
+					droppedEvents["span_filter"] += span.Events().Len()
+					continue
+				}
+
+				spanProcessedEvents := 0
+				var spanRecords []spanLogRecord
+				var spanErrorRecords []plog.LogRecord
+				spanErrorEvents := 0
+				var primaryException plog.LogRecord
+				hasPrimaryException := false
+
+				// Process each event in the span
+				for l := 0; l < span.Events().Len(); l++ {
+					event := span.Events().At(l)
+					totalEvents++
+
+					// Skip if the event doesn't pass the event filters
+					if reason := c.eventDropReason(event); reason != "" {
+						droppedEvents[reason]++
+						continue
+					}
+
+					// Skip if the event doesn't pass the OTTL filter conditions
+					if c.filterConditions != nil && !c.matchesFilterConditions(ctx, event, span, scopeSpans, resourceSpans) {
+						droppedEvents["filter_conditions"]++
+						continue
+					}
+
+					processedEvents++
+					spanProcessedEvents++
+
+					// Fold further exception events into the span's primary exception record if configured
+					if c.config.AggregateExceptions && event.Name() == "exception" && hasPrimaryException {
+						appendExceptionCause(primaryException, event, c.eventAttributeFilter, batch.attributeBudget)
+						// Folded events still count toward the span's error events
+						if c.config.ErrorEscalationThreshold > 0 && isErrorSeverity(c.eventSeverityNumber(event, span)) {
+							spanErrorEvents++
+						}
+						continue
+					}
+
+					// LAZY CREATION: Only create ResourceLogs and ScopeLogs when we have an event to process
+					resourceLogs := getResourceLogs()
+
+					// Find or create the ScopeLogs entry for this scope within the current ResourceLogs
+					var scopeLogs plog.ScopeLogs
+					scopeVersion, hasScopeVersion := c.scopeVersionFromAttribute(event, resource)
+					if scopeName, ok := c.scopeNameFromAttribute(event, resource); ok {
+						scopeLogs = findOrCreateNamedScopeLogs(resourceLogs, scopeName, scopeVersion)
+					} else if c.config.ScopePerEventName {
+						scopeLogs = findOrCreateNamedScopeLogs(resourceLogs, event.Name(), scopeVersion)
+					} else if hasScopeVersion {
+						versionedScope := pcommon.NewInstrumentationScope()
+						scope.CopyTo(versionedScope)
+						versionedScope.SetVersion(scopeVersion)
+						scopeLogs = findOrCreateScopeLogs(resourceLogs, versionedScope, scopeSpans.SchemaUrl())
+					} else {
+						scopeLogs = findOrCreateScopeLogs(resourceLogs, scope, scopeSpans.SchemaUrl())
+					}
+
+					// Create and append the log record to the correct ScopeLogs
+					logRecord := scopeLogs.LogRecords().AppendEmpty()
+					c.populateLogRecord(ctx, logRecord, event, l, span, scope, resource, batch)
+
+					// Record where the event was read from if configured
+					if c.config.AnnotateSourceIndex {
+						logRecord.Attributes().PutInt("spaneventtolog.source_resource_index", int64(i))
+						logRecord.Attributes().PutInt("spaneventtolog.source_span_index", int64(k))
+					}
+
+					if c.config.AggregateExceptions && event.Name() == "exception" {
+						c.startExceptionAggregate(logRecord, event)
+						primaryException = logRecord
+						hasPrimaryException = true
+					}
+
+					// Bound the attributes added across the batch if configured, then finish the record
+					// once the span's records have been escalated
+					charged := batch.attributeBudget.enforce(logRecord, 0)
+					spanRecords = append(spanRecords, spanLogRecord{logRecord, scopeLogs, resourceLogs, charged})
+
+					if c.config.ErrorEscalationThreshold > 0 && isErrorSeverity(logRecord.SeverityNumber()) {
+						spanErrorRecords = append(spanErrorRecords, logRecord)
+						spanErrorEvents++
+					}
+				}
+
+				// Escalate the span's error records to fatal if it has too many of them, before they are
+				// transformed
+				if c.config.ErrorEscalationThreshold > 0 && spanErrorEvents > c.config.ErrorEscalationThreshold {
+					for _, logRecord := range spanErrorRecords {
+						c.escalateToFatal(logRecord)
+					}
+				}
+				for _, record := range spanRecords {
+					finishLogRecord(record)
+				}
+
+				// Note the absence of matching events on spans of interest if configured
+				if spanProcessedEvents == 0 && c.shouldEmitAbsenceLog(span) {
+					resourceLogs := getResourceLogs()
+					scopeLogs := findOrCreateScopeLogs(resourceLogs, scope, scopeSpans.SchemaUrl())
+					logRecord := scopeLogs.LogRecords().AppendEmpty()
+					c.populateAbsenceLogRecord(logRecord, span, batch.attributeBudget)
+					finishLogRecord(spanLogRecord{logRecord: logRecord, scopeLogs: scopeLogs, resourceLogs: resourceLogs})
+				}
+
+				// Note spans whose events were all filtered out if configured
+				if spanProcessedEvents == 0 && span.Events().Len() > 0 && c.config.EmitFullyFilteredSpanLog {
+					resourceLogs := getResourceLogs()
+					scopeLogs := findOrCreateScopeLogs(resourceLogs, scope, scopeSpans.SchemaUrl())
+					logRecord := scopeLogs.LogRecords().AppendEmpty()
+					c.populateFullyFilteredLogRecord(logRecord, span)
+					finishLogRecord(spanLogRecord{logRecord: logRecord, scopeLogs: scopeLogs, resourceLogs: resourceLogs})
+				}
+			}
+		}
+	}
+
+	// Drop records duplicating an earlier one in the batch if configured
+	if c.config.DedupeWithinBatch {
+		if removed := dedupeLogRecords(logs, firstResourceLogs, c.recordUniqueAttributes()); removed > 0 {
+			otelSpan.SetAttributes(attribute.Int("duplicates_dropped", removed))
+		}
+	}
+
+	// Record the number of converted events on each resource if configured
+	if c.config.AnnotateResourceEventCount {
+		for i := firstResourceLogs; i < logs.ResourceLogs().Len(); i++ {
+			resourceLogs := logs.ResourceLogs().At(i)
+			count := 0
+			for j := 0; j < resourceLogs.ScopeLogs().Len(); j++ {
+				count += resourceLogs.ScopeLogs().At(j).LogRecords().Len()
+			}
+			resourceLogs.Resource().Attributes().PutInt("spaneventtolog.converted_events", int64(count))
+		}
+	}
+
+	// Record attributes removed to honor the batch attribute cap
+	if batch.attributeBudget != nil && batch.attributeBudget.truncated > 0 {
+		truncated := batch.attributeBudget.truncated
+		c.truncatedAttributes.Add(ctx, int64(truncated))
+		otelSpan.SetAttributes(attribute.Int("attributes_truncated", truncated))
+	}
+
+	c.eventsProcessed.Add(int64(processedEvents))
+
+	// Break the counts down by severity and drop reason if configured
+	if c.config.DetailedSpanAttributes {
+		otelSpan.SetAttributes(detailedSpanAttributes(logs, firstResourceLogs, droppedEvents)...)
+	}
+
+	otelSpan.SetAttributes(
+		attribute.Int("total_events_found", totalEvents),
+		attribute.Int("events_processed", processedEvents),
+		attribute.Int("logs_created", logs.LogRecordCount()-existingRecords),
+	)
+
+	// Mark each ResourceLogs as produced by the connector if configured
+	if c.config.InjectConnectorScope {
+		for i := firstResourceLogs; i < logs.ResourceLogs().Len(); i++ {
+			c.appendConnectorScope(logs.ResourceLogs().At(i))
+		}
+	}
+}
+
+// appendConnectorScope appends a ScopeLogs named after the connector to the ResourceLogs, holding a
+// single marker record identifying the connector instance.
+func (c *Connector) appendConnectorScope(resourceLogs plog.ResourceLogs) {
+	scopeLogs := resourceLogs.ScopeLogs().AppendEmpty()
+	scopeLogs.Scope().SetName(metadata.Type.String())
+
+	now := pcommon.NewTimestampFromTime(c.now())
+	logRecord := scopeLogs.LogRecords().AppendEmpty()
+	logRecord.SetTimestamp(now)
+	logRecord.SetObservedTimestamp(now)
+	logRecord.SetSeverityNumber(plog.SeverityNumberInfo)
+	logRecord.SetSeverityText("info")
+	logRecord.Body().SetStr("produced by " + c.componentID)
+	logRecord.Attributes().PutBool("spaneventtolog.marker", true)
+	logRecord.Attributes().PutStr("spaneventtolog.component_id", c.componentID)
+}
+
+// detailedSpanAttributes builds the per-severity counts of the log records in the ResourceLogs created
+// from the given index on, and the per-reason counts of dropped events, as span attributes.
+func detailedSpanAttributes(logs plog.Logs, firstResourceLogs int, droppedEvents map[string]int) []attribute.KeyValue {
+	bySeverity := map[string]int{}
+	for i := firstResourceLogs; i < logs.ResourceLogs().Len(); i++ {
+		resourceLogs := logs.ResourceLogs().At(i)
+		for j := 0; j < resourceLogs.ScopeLogs().Len(); j++ {
+			logRecords := resourceLogs.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < logRecords.Len(); k++ {
+				bySeverity[severityNumberToText(logRecords.At(k).SeverityNumber())]++
+			}
+		}
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(bySeverity)+len(droppedEvents))
+	for text, count := range bySeverity {
+		attrs = append(attrs, attribute.Int("logs_by_severity."+text, count))
+	}
+	for reason, count := range droppedEvents {
+		if count > 0 {
+			attrs = append(attrs, attribute.Int("events_dropped."+reason, count))
+		}
+	}
+	return attrs
+}
+
+// resolveSeverity determines the severity of a span event from the configured sources, in order of
+// precedence. Returns the severity number and text along with the name of the source that matched,
+// or "default" if none did.
+func (c *Connector) resolveSeverity(event ptrace.SpanEvent, span ptrace.Span) (plog.SeverityNumber, string, string) {
+	// An explicit severity number on the event overrides every other source if honored
+	if c.config.HonorExplicitSeverityNumberAttribute {
+		if v, exists := event.Attributes().Get(explicitSeverityNumberAttribute); exists {
+			if severityNumber, ok := severityNumberFromValue(v); ok && severityNumber >= plog.SeverityNumberTrace && severityNumber <= plog.SeverityNumberFatal4 {
+				return severityNumber, severityNumberToText(severityNumber), "explicit_severity_number"
+			}
+		}
+	}
+
+	// A custom resolver takes precedence over all configured sources
+	if c.severityResolver != nil {
+		if severityNumber, severityText, ok := c.severityResolver.Resolve(event, span); ok {
+			return severityNumber, severityText, "severity_resolver"
+		}
+	}
+
+	// Default severity, info unless configured otherwise
+	severityNumber := plog.SeverityNumberInfo
+	severityText := "info"
+	if c.config.DefaultSeverity != "" {
+		severityNumber, severityText = c.mapSeverity(c.config.DefaultSeverity)
+	}
+	severitySource := "default"
+	severityFound := false
+
+	// 1. Check AttributeMappings for severity (Highest Precedence)
+	if c.config.AttributeMappings.SeverityNumber != "" || c.config.AttributeMappings.SeverityText != "" {
+		if c.config.AttributeMappings.SeverityNumber != "" {
+			if attrValue, exists := event.Attributes().Get(c.config.AttributeMappings.SeverityNumber); exists {
+				if mappedNumber, ok := severityNumberFromValue(attrValue); ok {
+					severityNumber = mappedNumber
+					// Derive severity text from the mapped number to keep them in sync
+					severityText = severityNumberToText(severityNumber)
+					severitySource = "attribute_mappings"
+					severityFound = true
+				}
+			}
+		}
+		if c.config.AttributeMappings.SeverityText != "" {
+			if attrValue, exists := event.Attributes().Get(c.config.AttributeMappings.SeverityText); exists && attrValue.Type() == pcommon.ValueTypeStr {
+				severityText = attrValue.Str()
+				// If we don't have severity number from attribute mapping, try to parse from text
+				if !severityFound {
+					parsedNumber, parsedText := c.mapAttributeSeverity(severityText)
+					if parsedNumber != plog.SeverityNumberUnspecified {
+						severityNumber = parsedNumber
+						severityText = parsedText
+					}
+				}
+				severitySource = "attribute_mappings"
+				severityFound = true
+			}
+		}
+	}
+
+	// 2. Check SeverityAttribute (High Precedence)
+	if !severityFound && c.config.SeverityAttribute != "" {
+		if attrValue, exists := event.Attributes().Get(c.config.SeverityAttribute); exists && attrValue.Type() == pcommon.ValueTypeStr {
+			parsedNumber, parsedText := c.mapAttributeSeverity(attrValue.Str())
+			if parsedNumber != plog.SeverityNumberUnspecified {
+				severityNumber = parsedNumber
+				severityText = parsedText
+				severitySource = "severity_attribute"
+				severityFound = true
+			}
+		}
+	}
+
+	// 3. Check SeverityByAttributePresence (First Present Key)
+	if !severityFound && len(c.config.SeverityByAttributePresence) > 0 {
+		event.Attributes().Range(func(k string, _ pcommon.Value) bool {
+			configuredSeverity, exists := c.config.SeverityByAttributePresence[k]
+			if !exists {
+				return true
+			}
+			parsedNumber, parsedText := c.mapSeverity(configuredSeverity)
+			if parsedNumber == plog.SeverityNumberUnspecified {
+				return true
+			}
+			severityNumber = parsedNumber
+			severityText = parsedText
+			severitySource = "severity_by_attribute_presence"
+			severityFound = true
+			return false
+		})
+	}
+
+	// 4. Check SeverityByNumericThreshold (Highest Threshold Exceeded)
+	if !severityFound && c.config.SeverityByNumericThreshold.Attribute != "" {
+		if attrValue, exists := event.Attributes().Get(c.config.SeverityByNumericThreshold.Attribute); exists {
+			if value, ok := numericValue(attrValue); ok {
+				for _, rule := range c.numericThresholdRules {
+					if value > rule.Threshold {
+						severityNumber, severityText = c.mapSeverity(rule.Severity)
+						severitySource = "severity_by_numeric_threshold"
+						severityFound = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// 5. Check SeverityByEventName (Substring Match, Longest Precedence)
+	if !severityFound && len(c.config.SeverityByEventName) > 0 {
+		lowerEventName := strings.ToLower(event.Name())
+		longestMatchKeyLen := 0
+		matchedSeverityText := ""
+
+		for key, configuredSeverity := range c.config.SeverityByEventName {
+			lowerKey := strings.ToLower(key)
+			if strings.Contains(lowerEventName, lowerKey) {
+				if len(key) > longestMatchKeyLen {
+					// Check if the configuredSeverity is valid before accepting it
+					parsedNumber, parsedText := c.mapSeverity(configuredSeverity)
+					if parsedNumber != plog.SeverityNumberUnspecified {
+						longestMatchKeyLen = len(key)
+						matchedSeverityText = parsedText // Use the canonical text from mapSeverity
+					}
+				}
+			}
+		}
+
+		if matchedSeverityText != "" {
+			severityNumber, severityText = c.mapSeverity(matchedSeverityText) // Remap to get both Number and Text
+			severitySource = "severity_by_event_name"
+			severityFound = true
+		}
+	}
+
+	// 6. Check SeverityBySpanKind (Low Precedence)
+	if !severityFound && len(c.config.SeverityBySpanKind) > 0 {
+		if configuredSeverity, exists := c.config.SeverityBySpanKind[spanKindName(span.Kind())]; exists {
+			parsedNumber, parsedText := c.mapSeverity(configuredSeverity)
+			if parsedNumber != plog.SeverityNumberUnspecified {
+				severityNumber = parsedNumber
+				severityText = parsedText
+				severitySource = "severity_by_span_kind"
+				severityFound = true
+			}
+		}
+	}
+
+	// 7. Derive from the parent span status (Low Precedence); an unset status leaves the default
+	if !severityFound && c.config.SeverityFromSpanStatus {
+		switch span.Status().Code() {
+		case ptrace.StatusCodeError:
+			severityNumber, severityText = plog.SeverityNumberError, "error"
+			severitySource = "severity_from_span_status"
+			severityFound = true
+		case ptrace.StatusCodeOk:
+			severityNumber, severityText = plog.SeverityNumberInfo, "info"
+			severitySource = "severity_from_span_status"
+			severityFound = true
+		}
+	}
+
+	// 8. Fall back to SeverityForUnmatchedEvents (Lowest Precedence)
+	if !severityFound && c.config.SeverityForUnmatchedEvents != "" {
+		parsedNumber, parsedText := c.mapSeverity(c.config.SeverityForUnmatchedEvents)
+		if parsedNumber != plog.SeverityNumberUnspecified {
+			severityNumber = parsedNumber
+			severityText = parsedText
+			severitySource = "severity_for_unmatched_events"
+		}
+	}
+
+	return severityNumber, severityText, severitySource
+}
+
+// resolveTimestamp returns the timestamp from the first configured source yielding a non-zero value,
+// falling back to the event timestamp.
+func (c *Connector) resolveTimestamp(event ptrace.SpanEvent, span ptrace.Span) pcommon.Timestamp {
+	for _, source := range c.config.TimestampSources {
+		var timestamp pcommon.Timestamp
+		switch source {
+		case "event_time":
+			timestamp = event.Timestamp()
+		case "span_start":
+			timestamp = span.StartTimestamp()
+		case "span_end":
+			timestamp = span.EndTimestamp()
+		case "now":
+			timestamp = pcommon.NewTimestampFromTime(c.now())
+		default:
+			if key, found := strings.CutPrefix(source, "attribute:"); found {
+				timestamp = timestampFromAttribute(event.Attributes(), key)
+			}
+		}
+		if timestamp != 0 {
+			return timestamp
+		}
+	}
+
+	// Avoid zero timestamps, which downstream systems reject, if configured
+	if event.Timestamp() == 0 && c.config.TimestampFallback {
+		if span.StartTimestamp() != 0 {
+			return span.StartTimestamp()
+		}
+		return pcommon.NewTimestampFromTime(c.now())
+	}
+	return event.Timestamp()
+}
+
+// timestampFromAttribute reads a timestamp from an attribute holding either Unix nanoseconds or an
+// RFC 3339 string. Returns zero if the attribute is missing or can't be parsed.
+func timestampFromAttribute(attrs pcommon.Map, key string) pcommon.Timestamp {
+	v, exists := attrs.Get(key)
+	if !exists {
+		return 0
+	}
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		if v.Int() > 0 {
+			return pcommon.Timestamp(v.Int())
+		}
+	case pcommon.ValueTypeStr:
+		if t, err := time.Parse(time.RFC3339Nano, v.Str()); err == nil {
+			return pcommon.NewTimestampFromTime(t)
+		}
+	}
+	return 0
+}
+
+// setBody sets the log record body according to the configured body mode.
+func (c *Connector) setBody(logRecord plog.LogRecord, event ptrace.SpanEvent) {
+	if c.config.BodyMode == "attributes_map" {
+		copyUndeniedAttributes(logRecord.Body().SetEmptyMap(), event.Attributes(), c.eventAttributeFilter)
+		return
+	}
+
+	// Set body using attribute mapping, then the secondary attribute, or fallback to event name
+	for _, key := range []string{c.config.AttributeMappings.Body, c.config.SecondaryBodyAttribute} {
+		if key == "" {
+			continue
+		}
+		attrValue, exists := event.Attributes().Get(key)
+		if !exists {
+			continue
+		}
+		if attrValue.Type() == pcommon.ValueTypeStr {
+			logRecord.Body().SetStr(attrValue.Str())
+			return
+		}
+		// Encode bytes into a string body if configured
+		if attrValue.Type() == pcommon.ValueTypeBytes {
+			switch c.config.BodyBytesEncoding {
+			case "base64":
+				logRecord.Body().SetStr(base64.StdEncoding.EncodeToString(attrValue.Bytes().AsRaw()))
+				return
+			case "hex":
+				logRecord.Body().SetStr(hex.EncodeToString(attrValue.Bytes().AsRaw()))
+				return
+			}
+		}
+		// Join string slices into a single body line if configured, falling back to the event name
+		// rather than the next attribute for other slices
+		if attrValue.Type() == pcommon.ValueTypeSlice && c.config.BodySliceJoin != "" {
+			joined, ok := joinStringSlice(attrValue.Slice(), c.config.BodySliceJoin)
+			if !ok {
+				break
+			}
+			logRecord.Body().SetStr(joined)
+			return
+		}
+	}
+
+	// Fallback to event name, or the configured default body for unnamed events
+	if event.Name() == "" && c.config.DefaultBody != "" {
+		logRecord.Body().SetStr(c.config.DefaultBody)
+	} else {
+		logRecord.Body().SetStr(event.Name())
+	}
+}
+
+// joinStringSlice joins the elements of a slice with the separator. Returns false if any element
+// is not a string.
+func joinStringSlice(slice pcommon.Slice, sep string) (string, bool) {
+	parts := make([]string, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		if slice.At(i).Type() != pcommon.ValueTypeStr {
+			return "", false
+		}
+		parts = append(parts, slice.At(i).Str())
+	}
+	return strings.Join(parts, sep), true
+}
+
+// includeSpan determines if events from a span pass the span filters.
+func (c *Connector) includeSpan(span ptrace.Span) bool {
+	// Skip if we're filtering by span kind and this kind is not included
+	if c.spanKindSet != nil {
+		if _, exists := c.spanKindSet[span.Kind()]; !exists {
+			return false
+		}
+	}
+
+	// Skip unless the span carries all required attributes
+	if len(c.config.RequireSpanAttributes) > 0 && !hasRequiredAttributes(span.Attributes(), c.config.RequireSpanAttributes) {
+		return false
+	}
+
+	// Skip if we're filtering by span status and this status is not included
+	if c.spanStatusSet != nil {
+		if _, exists := c.spanStatusSet[span.Status().Code()]; !exists {
+			return false
+		}
+	}
+
+	return c.includeSpanName(span.Name())
+}
+
+// hasRequiredAttributes determines if attrs carries every required attribute with the matching
+// string value. An empty required value only requires the attribute to be present.
+func hasRequiredAttributes(attrs pcommon.Map, required map[string]string) bool {
+	for key, expected := range required {
+		v, exists := attrs.Get(key)
+		if !exists {
+			return false
+		}
+		if expected != "" && v.AsString() != expected {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRequiredEventAttributes determines if attrs contains every required key with a matching value.
+func hasRequiredEventAttributes(attrs pcommon.Map, required map[string]string) bool {
+	for key, expected := range required {
+		v, exists := attrs.Get(key)
+		if !exists || !attributeValueEquals(v, expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeValueEquals compares an attribute value with a configured string according to the
+// value's type, so that "true" matches a bool attribute and "42" an int attribute.
+func attributeValueEquals(v pcommon.Value, expected string) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeBool:
+		b, err := strconv.ParseBool(expected)
+		return err == nil && b == v.Bool()
+	case pcommon.ValueTypeInt:
+		i, err := strconv.ParseInt(expected, 10, 64)
+		return err == nil && i == v.Int()
+	case pcommon.ValueTypeDouble:
+		f, err := strconv.ParseFloat(expected, 64)
+		return err == nil && f == v.Double()
+	default:
+		return v.AsString() == expected
+	}
+}
+
+// includeSpanName determines if events from a span with the given name pass the span name patterns.
+func (c *Connector) includeSpanName(name string) bool {
+	if len(c.spanNamePatterns) == 0 {
+		return true
+	}
+	for _, re := range c.spanNamePatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventDropReason names the first event filter that rejects an event, or returns an empty string
+// if the event passes all of them.
+func (c *Connector) eventDropReason(event ptrace.SpanEvent) string {
+	// Skip if we're filtering by event name and this event is not included
+	if !c.includeEventName(event.Name()) {
+		return "event_name"
+	}
+
+	// Skip if the event is missing a required attribute value
+	if len(c.config.RequireEventAttributes) > 0 && !hasRequiredEventAttributes(event.Attributes(), c.config.RequireEventAttributes) {
+		return "required_attributes"
+	}
+
+	// Skip if we're filtering by numeric attribute ranges and none matches
+	if len(c.config.NumericAttributeFilters) > 0 && !c.matchesNumericAttributeFilters(event.Attributes()) {
+		return "numeric_attribute"
+	}
+
+	// Skip if the event outlived its TTL
+	if c.config.TTLAttribute != "" && c.isExpired(event) {
+		return "ttl"
+	}
+
+	// Skip if the event falls outside the time window
+	if !c.withinEventTimeWindow(event) {
+		return "time_window"
+	}
+
+	return ""
+}
+
+// eventSeverityNumber returns the severity number a log record created from the event would get,
+// once resolved and downgraded.
+func (c *Connector) eventSeverityNumber(event ptrace.SpanEvent, span ptrace.Span) plog.SeverityNumber {
+	severityNumber, _, _ := c.resolveSeverity(event, span)
+	if downgraded, exists := c.downgradeSeverities[severityNumber]; exists {
+		severityNumber = downgraded
+	}
+	return severityNumber
+}
+
+// escalateToFatal raises the severity of a log record to fatal, along with the "level" attribute
+// when it was added from the severity text. A configured downgrade of fatal still applies, so that
+// downgrades take effect after escalation.
+func (c *Connector) escalateToFatal(logRecord plog.LogRecord) {
+	severityNumber := plog.SeverityNumberFatal
+	if downgraded, exists := c.downgradeSeverities[severityNumber]; exists {
+		severityNumber = downgraded
+	}
+	severityText := severityNumberToText(severityNumber)
+	if level, exists := logRecord.Attributes().Get("level"); c.config.AddLevel && exists && level.Str() == logRecord.SeverityText() {
+		level.SetStr(severityText)
+	}
+	logRecord.SetSeverityNumber(severityNumber)
+	logRecord.SetSeverityText(severityText)
+}
+
+// transformLogRecord runs the OTTL transform statements against a populated log record, if configured.
+// Statements failing at runtime are logged by the statement sequence and don't drop the record.
+func (c *Connector) transformLogRecord(ctx context.Context, logRecord plog.LogRecord, scopeLogs plog.ScopeLogs, resourceLogs plog.ResourceLogs) {
+	if c.transformStatements == nil {
+		return
+	}
+	tCtx := ottllog.NewTransformContext(logRecord, scopeLogs.Scope(), resourceLogs.Resource(), scopeLogs, resourceLogs)
+	if err := c.transformStatements.Execute(ctx, tCtx); err != nil {
+		c.logger.Warn("Failed to run transform statements", zap.Error(err))
+	}
+}
+
+// matchesFilterConditions determines if the event passes all OTTL filter conditions. Events for
+// which a condition fails to evaluate don't pass.
+func (c *Connector) matchesFilterConditions(ctx context.Context, event ptrace.SpanEvent, span ptrace.Span, scopeSpans ptrace.ScopeSpans, resourceSpans ptrace.ResourceSpans) bool {
+	tCtx := ottlspanevent.NewTransformContext(event, span, scopeSpans.Scope(), resourceSpans.Resource(), scopeSpans, resourceSpans)
+	matched, err := c.filterConditions.Eval(ctx, tCtx)
+	if err != nil {
+		c.logger.Debug("Failed to evaluate filter conditions", zap.String("event.name", event.Name()), zap.Error(err))
+		return false
+	}
+	return matched
+}
+
+// withinEventTimeWindow determines if the event timestamp falls within the configured time window,
+// bounds included.
+func (c *Connector) withinEventTimeWindow(event ptrace.SpanEvent) bool {
+	if c.minEventTime.IsZero() && c.maxEventTime.IsZero() {
+		return true
+	}
+	eventTime := event.Timestamp().AsTime()
+	if !c.minEventTime.IsZero() && eventTime.Before(c.minEventTime) {
+		return false
+	}
+	return c.maxEventTime.IsZero() || !eventTime.After(c.maxEventTime)
+}
+
+// matchesNumericAttributeFilters determines if any numeric attribute falls within its configured range.
+func (c *Connector) matchesNumericAttributeFilters(attrs pcommon.Map) bool {
+	for _, filter := range c.config.NumericAttributeFilters {
+		v, exists := attrs.Get(filter.Key)
+		if !exists {
+			continue
+		}
+
+		value, ok := numericValue(v)
+		if !ok {
+			continue
+		}
+
+		if value >= filter.Min && value <= filter.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// isExpired determines if the event is older than the number of seconds held by its TTL attribute.
+// Events without a timestamp or a numeric TTL never expire.
+func (c *Connector) isExpired(event ptrace.SpanEvent) bool {
+	if event.Timestamp() == 0 {
+		return false
+	}
+	v, exists := event.Attributes().Get(c.config.TTLAttribute)
+	if !exists {
+		return false
+	}
+	ttlSeconds, ok := numericValue(v)
+	if !ok {
+		return false
+	}
+	age := c.now().Sub(event.Timestamp().AsTime())
+	return age.Seconds() > ttlSeconds
+}
+
+// truncateAttributes removes the attributes of attrs beyond the first limit, in insertion order.
+// Returns the number of attributes removed.
+func truncateAttributes(attrs pcommon.Map, limit int) int {
+	if limit < 0 {
+		limit = 0
+	}
+	removed := 0
+	kept := 0
+	attrs.RemoveIf(func(string, pcommon.Value) bool {
+		if kept < limit {
+			kept++
+			return false
+		}
+		removed++
+		return true
+	})
+	return removed
+}
+
+// numericValue returns the value of an int or double attribute as a float64.
+func numericValue(v pcommon.Value) (float64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.Int()), true
+	case pcommon.ValueTypeDouble:
+		return v.Double(), true
+	}
+	return 0, false
+}
+
+// includeEventName determines if an event with the given name passes the event name filters.
+// Negated patterns take precedence over IncludeEventNames and inclusion patterns.
+func (c *Connector) includeEventName(name string) bool {
+	if _, exists := c.excludeEventNameSet[name]; exists {
+		return false
+	}
+	for _, re := range c.excludeEventPatterns {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+
+	// Without any inclusion rule, every event that isn't excluded is included
+	if c.eventNameSet == nil && len(c.includeEventPatterns) == 0 && len(c.config.IncludeEventNameGlobs) == 0 {
+		return true
+	}
+
+	if _, exists := c.eventNameSet[name]; exists {
+		return true
+	}
+	for _, re := range c.includeEventPatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return c.matchesEventNameGlob(name)
+}
+
+// matchesEventNameGlob determines if the event name matches any of the configured globs.
+func (c *Connector) matchesEventNameGlob(name string) bool {
+	for _, glob := range c.config.IncludeEventNameGlobs {
+		// Globs are validated at startup, so errors cannot occur here
+		if matched, _ := path.Match(glob, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// populateLogRecord populates a log record based on a span event. eventIndex is the index of the
+// event within the span's events.
+func (c *Connector) populateLogRecord(
+	ctx context.Context,
+	logRecord plog.LogRecord,
+	event ptrace.SpanEvent,
+	eventIndex int,
+	span ptrace.Span,
+	scope pcommon.InstrumentationScope,
+	resource pcommon.Resource,
+	batch batchState,
+) {
+	// Resolve severity from the configured sources
+	severityNumber, severityText, severitySource := c.resolveSeverity(event, span)
+	if downgraded, exists := c.downgradeSeverities[severityNumber]; exists {
+		severityNumber, severityText = downgraded, severityToTextMap[downgraded]
+	}
+	if severityNumber == plog.SeverityNumberUnspecified && c.config.UnspecifiedSeverityText != nil {
+		severityText = *c.config.UnspecifiedSeverityText
+	}
+	if c.config.DebugTraceSeverityResolution {
+		trace.SpanFromContext(ctx).AddEvent("severity_resolution", trace.WithAttributes(
+			attribute.String("event.name", event.Name()),
+			attribute.String("severity.source", severitySource),
+			attribute.Int("severity.number", int(severityNumber)),
+			attribute.String("severity.text", severityText),
+		))
+	}
+
+	// Set timestamp from the configured sources, defaulting to the event timestamp
+	logRecord.SetTimestamp(c.resolveTimestamp(event, span))
+
+	// Set observed timestamp from the configured source, unless it should be left unset
+	if observed, ok := c.observedTimestamp(event.Timestamp()); ok {
+		logRecord.SetObservedTimestamp(observed)
+	}
+
+	// Set the determined severity (or default if not found)
+	logRecord.SetSeverityNumber(severityNumber)
+	logRecord.SetSeverityText(severityText)
+
+	// Preserve the severity text as it appeared on the event if configured
+	if c.config.PreserveOriginalSeverityText != "" {
+		if originalText, ok := c.originalSeverityText(event, severitySource); ok {
+			logRecord.Attributes().PutStr(c.config.PreserveOriginalSeverityText, originalText)
+		}
+	}
+
+	// Set body from the event
+	c.setBody(logRecord, event)
+
+	// Copy event attributes if configured, unless they already form the body
+	if c.shouldCopyAttributes("event.attributes") && c.config.BodyMode != "attributes_map" {
+		c.copyAttributes(logRecord.Attributes(), event.Attributes(), c.eventAttributeFilter, "", batch.attributeBudget)
+
+		// Rename legacy attribute keys to their semantic-convention keys if configured
+		if renames, ok := semConvRenames[c.config.SemConvMode]; ok {
+			applySemConvRenames(logRecord.Attributes(), renames)
+		}
+
+		// Split the stacktrace into frames if configured
+		if c.config.ParseStacktrace {
+			c.parseStacktrace(logRecord.Attributes())
+		}
+
+		// Add the short class name of the exception type if configured
+		if c.config.ShortenExceptionType {
+			shortenExceptionType(logRecord.Attributes())
+		}
+	}
+
+	// Promote the named groups matched in the body to attributes if configured
+	if c.bodyExtractPattern != nil {
+		c.extractBodyFields(logRecord)
+	}
+
+	// Preserve the whole event as a nested map if configured
+	if c.config.PreserveEventAsMap {
+		preserveEventAsMap(logRecord.Attributes().PutEmptyMap("span.event"), event, c.eventAttributeFilter)
+	}
+
+	// Collapse event attributes into a single JSON attribute if configured
+	if len(c.config.CollapseAttributesToJSON.SourceKeys) > 0 {
+		c.collapseAttributesToJSON(logRecord.Attributes(), event.Attributes())
+	}
+
+	// Preserve event name as attribute if configured
+	if c.config.AttributeMappings.EventName != "" {
+		logRecord.Attributes().PutStr(c.config.AttributeMappings.EventName, event.Name())
+	}
+
+	// Add the normalized event name if configured
+	if c.config.IncludeNormalizedEventName {
+		logRecord.Attributes().PutStr("event.name.normalized", strings.ToLower(strings.TrimSpace(event.Name())))
+	}
+
+	// Add level attribute if configured and not already present
+	if c.config.AddLevel {
+		// Check if level attribute already exists in log record attributes
+		_, hasLevel := logRecord.Attributes().Get("level")
+		if !hasLevel {
+			// Add level attribute based on severity text
+			logRecord.Attributes().PutStr("level", logRecord.SeverityText())
+		}
+	}
+
+	// Copy span attributes if configured
+	if c.shouldCopyAttributes("span.attributes") {
+		c.copyAttributes(logRecord.Attributes(), span.Attributes(), c.spanAttributeFilter, c.config.SpanAttributePrefix, batch.attributeBudget)
+	}
+
+	// Copy service identity from the resource if configured
+	if c.config.IncludeServiceVersion {
+		for _, key := range []string{"service.name", "service.version"} {
+			if v, exists := resource.Attributes().Get(key); exists {
+				v.CopyTo(logRecord.Attributes().PutEmpty(c.config.ResourceAttributePrefix + key))
+			}
+		}
+	}
+
+	// Move copied attributes to their configured keys
+	if len(c.config.AttributeRenames) > 0 {
+		renameAttributes(logRecord.Attributes(), c.config.AttributeRenames)
+	}
+
+	// Stamp the collector hostname if configured and resolved
+	if c.collectorHostname != "" {
+		logRecord.Attributes().PutStr("collector.hostname", c.collectorHostname)
+	}
+
+	// Record the source scope if configured
+	if c.config.AnnotateSourceScope {
+		logRecord.Attributes().PutStr("spaneventtolog.source_scope", scope.Name())
+	}
+
+	// Record the number of source scope attributes if configured
+	if c.config.AnnotateScopeAttributeCount {
+		logRecord.Attributes().PutInt("otel.scope.attribute_count", int64(scope.Attributes().Len()))
+	}
+
+	// Stamp the output schema version if configured
+	if c.config.SchemaVersionAttribute != "" {
+		logRecord.Attributes().PutStr(c.config.SchemaVersionAttribute, c.config.SchemaVersion)
+	}
+
+	// Record the size of the source batch if configured
+	if c.config.AnnotateBatchSize {
+		logRecord.Attributes().PutInt("spaneventtolog.batch_span_count", int64(batch.spanCount))
+	}
+
+	// Stamp routing attributes if configured
+	for key, tmpl := range c.routingTemplates {
+		logRecord.Attributes().PutStr(key, renderTemplate(tmpl, event, span, resource))
+	}
+
+	// Build the correlation key if configured
+	if c.correlationKeyTemplate != nil {
+		logRecord.Attributes().PutStr(c.config.CorrelationKey.Attribute, renderTemplate(c.correlationKeyTemplate, event, span, resource))
+	}
+
+	// Add a deterministic record ID if configured
+	if c.config.GenerateRecordID {
+		logRecord.Attributes().PutStr("log.record.id", recordID(span, eventIndex, event.Name()))
+	}
+
+	// Add span name hash if configured
+	if c.config.IncludeSpanNameHash {
+		logRecord.Attributes().PutStr("span.name_hash", spanNameHash(span.Name()))
+	}
+
+	// Add span status code if configured
+	if c.config.IncludeStatusCode {
+		logRecord.Attributes().PutStr("span.status_code", span.Status().Code().String())
+	}
+
+	// Add the span status message alongside the status code or severities derived from the span status
+	if (c.config.IncludeStatusCode || c.config.SeverityFromSpanStatus) && span.Status().Message() != "" {
+		logRecord.Attributes().PutStr("span.status_message", span.Status().Message())
+	}
+
+	// Record whether the parent span is a trace root if configured
+	if c.config.IncludeIsRoot {
+		logRecord.Attributes().PutBool("span.is_root", span.ParentSpanID().IsEmpty())
+	}
+
+	// Record the duration of the parent span if configured and the span has ended
+	if c.config.IncludeSpanDuration {
+		c.putSpanDuration(logRecord.Attributes(), span)
+	}
+
+	// Add trace and span ID fields if configured, unless skipped for the event
+	if c.shouldIncludeSpanContext(span) && !c.eventSkipsSpanContext(event) {
+		c.setSpanContext(logRecord, span)
+	}
+
+	// Account for attributes dropped at instrumentation time if configured
+	if c.config.AccumulateDroppedCounts {
+		logRecord.SetDroppedAttributesCount(logRecord.DroppedAttributesCount() + event.DroppedAttributesCount())
+	}
+}
+
+// putSpanDuration adds the duration of the span in the configured unit. Nothing is added unless both
+// span timestamps are set and the span didn't end before it started.
+func (c *Connector) putSpanDuration(attrs pcommon.Map, span ptrace.Span) {
+	start, end := span.StartTimestamp(), span.EndTimestamp()
+	if start == 0 || end == 0 || end < start {
+		return
+	}
+	duration := time.Duration(end - start)
+	switch c.config.SpanDurationUnit {
+	case "ns":
+		attrs.PutInt("span.duration_ns", duration.Nanoseconds())
+	case "s":
+		attrs.PutDouble("span.duration_s", duration.Seconds())
+	default:
+		attrs.PutDouble("span.duration_ms", float64(duration)/float64(time.Millisecond))
+	}
+}
+
+// setSpanContext sets the trace and span IDs on the log record along with span identifying attributes.
+func (c *Connector) setSpanContext(logRecord plog.LogRecord, span ptrace.Span) {
+	logRecord.SetTraceID(span.TraceID())
+	logRecord.SetSpanID(span.SpanID())
+
+	// Set flags; the lower 8 bits of both span and log record flags hold the W3C trace flags
+	logRecord.SetFlags(plog.LogRecordFlags(span.Flags() & spanFlagsTraceFlagsMask))
+
+	// Add trace state
+	if span.TraceState().AsRaw() != "" {
+		logRecord.Attributes().PutStr("trace.state", span.TraceState().AsRaw())
+	}
+
+	// Add span name
+	logRecord.Attributes().PutStr("span.name", span.Name())
 
-	return nil
-}
+	// Add span kind
+	logRecord.Attributes().PutStr("span.kind", span.Kind().String())
 
-// Start implements the component.Component interface.
-func (c *Connector) Start(_ context.Context, _ component.Host) error {
-	return nil
-}
+	// Add the parent span ID, unless the span is a trace root
+	if !span.ParentSpanID().IsEmpty() {
+		logRecord.Attributes().PutStr("span.parent_id", span.ParentSpanID().String())
+	}
 
-// Shutdown implements the component.Component interface.
-func (c *Connector) Shutdown(_ context.Context) error {
-	return nil
-}
+	// Add the raw W3C trace flags if configured
+	if c.config.IncludeTraceFlagsInt {
+		logRecord.Attributes().PutInt("trace.flags", int64(span.Flags()&spanFlagsTraceFlagsMask))
+	}
 
-// findOrCreateResourceLogs finds existing ResourceLogs or creates a new one.
-// Returns the ResourceLogs and a boolean indicating if it was newly created.
-func findOrCreateResourceLogs(logs plog.Logs, res pcommon.Resource) (plog.ResourceLogs, bool) {
-	rls := logs.ResourceLogs()
-	for i := 0; i < rls.Len(); i++ {
-		rl := rls.At(i)
-		// Simple identity check, might need more robust comparison if attributes change
-		if rl.Resource() == res {
-			return rl, false
-		}
+	// Add the number of span links if configured
+	if c.config.IncludeSpanLinkCount {
+		logRecord.Attributes().PutInt("span.link_count", int64(span.Links().Len()))
 	}
-	newRl := rls.AppendEmpty()
-	res.CopyTo(newRl.Resource())
-	return newRl, true
 }
 
-// findOrCreateScopeLogs finds existing ScopeLogs or creates a new one within ResourceLogs.
-// Returns the ScopeLogs.
-func findOrCreateScopeLogs(rl plog.ResourceLogs, scope pcommon.InstrumentationScope) plog.ScopeLogs {
-	sls := rl.ScopeLogs()
-	for i := 0; i < sls.Len(); i++ {
-		sl := sls.At(i)
-		// Simple identity check
-		if sl.Scope() == scope {
-			return sl
+// shouldEmitAbsenceLog determines if the span carries all attributes listed in EmitAbsenceLogForSpanAttribute.
+func (c *Connector) shouldEmitAbsenceLog(span ptrace.Span) bool {
+	if len(c.config.EmitAbsenceLogForSpanAttribute) == 0 {
+		return false
+	}
+	for key, expected := range c.config.EmitAbsenceLogForSpanAttribute {
+		v, exists := span.Attributes().Get(key)
+		if !exists || v.AsString() != expected {
+			return false
 		}
 	}
-	newSl := sls.AppendEmpty()
-	scope.CopyTo(newSl.Scope())
-	return newSl
+	return true
 }
 
-// extractLogsFromTraces extracts logs from traces, grouping by resource and scope.
-func (c *Connector) extractLogsFromTraces(ctx context.Context, traces ptrace.Traces) plog.Logs {
-	_, otelSpan := c.tracer.Start(ctx, "connector/spaneventtolog/ExtractLogs")
-	defer otelSpan.End()
+// populateAbsenceLogRecord populates a log record noting that a span of interest had no matching events.
+func (c *Connector) populateAbsenceLogRecord(logRecord plog.LogRecord, span ptrace.Span, budget *attributeBudget) {
+	logRecord.SetTimestamp(spanSummaryTimestamp(span))
+	if observed, ok := c.observedTimestamp(logRecord.Timestamp()); ok {
+		logRecord.SetObservedTimestamp(observed)
+	}
+	logRecord.SetSeverityNumber(plog.SeverityNumberInfo)
+	logRecord.SetSeverityText("info")
+	logRecord.Body().SetStr("no matching events")
 
-	logs := plog.NewLogs()
+	if c.shouldCopyAttributes("span.attributes") {
+		c.copyAttributes(logRecord.Attributes(), span.Attributes(), c.spanAttributeFilter, c.config.SpanAttributePrefix, budget)
+	}
 
-	if traces.ResourceSpans().Len() == 0 {
-		otelSpan.SetAttributes(attribute.String("result", "no_resource_spans"))
-		return logs
+	if c.shouldIncludeSpanContext(span) {
+		c.setSpanContext(logRecord, span)
 	}
+}
 
-	totalEvents := 0
-	processedEvents := 0
+// populateFullyFilteredLogRecord populates a debug log record noting that all events of a span were
+// filtered out, along with how many were dropped.
+func (c *Connector) populateFullyFilteredLogRecord(logRecord plog.LogRecord, span ptrace.Span) {
+	logRecord.SetTimestamp(spanSummaryTimestamp(span))
+	if observed, ok := c.observedTimestamp(logRecord.Timestamp()); ok {
+		logRecord.SetObservedTimestamp(observed)
+	}
+	logRecord.SetSeverityNumber(plog.SeverityNumberDebug)
+	logRecord.SetSeverityText("debug")
+	logRecord.Body().SetStr("all events filtered")
+	logRecord.Attributes().PutInt("spaneventtolog.filtered_events", int64(span.Events().Len()))
 
-	for i := 0; i < traces.ResourceSpans().Len(); i++ {
-		resourceSpans := traces.ResourceSpans().At(i)
-		resource := resourceSpans.Resource()
+	if c.shouldIncludeSpanContext(span) {
+		c.setSpanContext(logRecord, span)
+	}
+}
 
-		for j := 0; j < resourceSpans.ScopeSpans().Len(); j++ {
-			scopeSpans := resourceSpans.ScopeSpans().At(j)
-			scope := scopeSpans.Scope()
+// observedTimestamp returns the observed timestamp of a log record converted from a source with the
+// given timestamp, per ObservedTimestamp. Returns false if it should be left unset.
+func (c *Connector) observedTimestamp(sourceTimestamp pcommon.Timestamp) (pcommon.Timestamp, bool) {
+	switch c.config.ObservedTimestamp {
+	case "event":
+		return sourceTimestamp, true
+	case "none":
+		return 0, false
+	default:
+		return pcommon.NewTimestampFromTime(c.now()), true
+	}
+}
 
-			for k := 0; k < scopeSpans.Spans().Len(); k++ {
-				span := scopeSpans.Spans().At(k)
+// spanSummaryTimestamp returns the timestamp for log records summarizing a span: its end
+// timestamp, or its start timestamp if the span has no end.
+func spanSummaryTimestamp(span ptrace.Span) pcommon.Timestamp {
+	if span.EndTimestamp() != 0 {
+		return span.EndTimestamp()
+	}
+	return span.StartTimestamp()
+}
 
-				// Process each event in the span
-				for l := 0; l < span.Events().Len(); l++ {
-					event := span.Events().At(l)
-					totalEvents++
+// copyAttributes copies the attributes from src permitted by filter into dst, applying the
+// configured value transformations. The prefix is prepended to each key once it has been renamed,
+// before its depth is limited. Attributes beyond the batch attribute budget are skipped.
+func (c *Connector) copyAttributes(dst, src pcommon.Map, filter attributeFilter, prefix string, budget *attributeBudget) {
+	src.Range(func(k string, v pcommon.Value) bool {
+		if !filter.permits(k) {
+			return true
+		}
+		renamed := c.renameKey(k)
+		// Denied keys are also checked after renaming, so that they never appear on the record
+		if filter.denies(renamed) {
+			return true
+		}
+		key, ok := c.limitKeyDepth(prefix + renamed)
+		if !ok {
+			return true
+		}
+		if !budget.allows(dst, key) {
+			return true
+		}
+		dstValue := dst.PutEmpty(key)
+		v.CopyTo(dstValue)
+		if !c.transformValue(dstValue) {
+			dst.Remove(key)
+		} else if c.config.FlattenAttributes && isNonEmptyCollection(dstValue) {
+			nested := pcommon.NewValueEmpty()
+			dstValue.CopyTo(nested)
+			dst.Remove(key)
+			c.putFlattened(dst, key, nested, budget)
+		} else if c.config.StringifyAllAttributes {
+			dstValue.SetStr(dstValue.AsString())
+		}
+		return true
+	})
+}
 
-					// Skip if we're filtering by event name and this event is not in the list
-					if c.eventNameSet != nil {
-						if _, exists := c.eventNameSet[event.Name()]; !exists {
-							continue
-						}
-					}
+// isNonEmptyCollection determines if a value is a map or slice holding at least one value.
+func isNonEmptyCollection(v pcommon.Value) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeMap:
+		return v.Map().Len() > 0
+	case pcommon.ValueTypeSlice:
+		return v.Slice().Len() > 0
+	}
+	return false
+}
 
-					processedEvents++
+// putFlattened puts the value in dst under key, recursively flattening non-empty maps into dotted
+// keys and non-empty slices into indexed keys (e.g. "http.status_code" and "tags.0"). The flattened
+// keys are limited to MaxKeyDepth, and their values stringified if configured. Keys beyond the
+// budget are skipped.
+func (c *Connector) putFlattened(dst pcommon.Map, key string, v pcommon.Value, budget *attributeBudget) {
+	switch {
+	case v.Type() == pcommon.ValueTypeMap && v.Map().Len() > 0:
+		v.Map().Range(func(k string, nested pcommon.Value) bool {
+			c.putFlattened(dst, key+"."+k, nested, budget)
+			return true
+		})
+	case v.Type() == pcommon.ValueTypeSlice && v.Slice().Len() > 0:
+		for i := 0; i < v.Slice().Len(); i++ {
+			c.putFlattened(dst, key+"."+strconv.Itoa(i), v.Slice().At(i), budget)
+		}
+	default:
+		flatKey, ok := c.limitKeyDepth(key)
+		if !ok || !budget.allows(dst, flatKey) {
+			return
+		}
+		if c.config.StringifyAllAttributes {
+			dst.PutStr(flatKey, v.AsString())
+		} else {
+			v.CopyTo(dst.PutEmpty(flatKey))
+		}
+	}
+}
 
-					// LAZY CREATION: Only create ResourceLogs and ScopeLogs when we have an event to process
-					resourceLogs, createdRl := findOrCreateResourceLogs(logs, resource)
-					if createdRl {
-						// Copy resource attributes only if configured and only when ResourceLogs is first created
-						if c.shouldCopyAttributes("resource.attributes") {
-							resource.Attributes().CopyTo(resourceLogs.Resource().Attributes())
-						} else {
-							// Ensure resourceLogs has a resource object, even if empty
-							resourceLogs.Resource().Attributes().Clear()
-						}
-					}
+// renameKey applies the first matching attribute rename rule to the key, if any.
+func (c *Connector) renameKey(key string) string {
+	for _, rule := range c.renameRules {
+		match := rule.pattern.FindStringSubmatchIndex(key)
+		if match == nil {
+			continue
+		}
+		// Only the matched portion is replaced; the rest of the key is kept
+		renamed := rule.pattern.ExpandString(nil, rule.replacement, key, match)
+		return key[:match[0]] + string(renamed) + key[match[1]:]
+	}
+	return key
+}
 
-					// Find or create the ScopeLogs entry for this scope within the current ResourceLogs
-					scopeLogs := findOrCreateScopeLogs(resourceLogs, scope)
+// limitKeyDepth truncates the key to MaxKeyDepth dot-separated segments, if configured.
+// Returns false if the key exceeds the depth and should be dropped.
+func (c *Connector) limitKeyDepth(key string) (string, bool) {
+	if c.config.MaxKeyDepth <= 0 {
+		return key, true
+	}
+	segments := strings.SplitN(key, ".", c.config.MaxKeyDepth+1)
+	if len(segments) <= c.config.MaxKeyDepth {
+		return key, true
+	}
+	if c.config.DropKeysExceedingDepth {
+		return "", false
+	}
+	return strings.Join(segments[:c.config.MaxKeyDepth], "."), true
+}
 
-					// Create and append the log record to the correct ScopeLogs
-					logRecord := scopeLogs.LogRecords().AppendEmpty()
-					c.populateLogRecord(logRecord, event, span)
-				}
-			}
+// transformValue applies the configured value transformations to a copied attribute value in place,
+// descending into maps and slices. Returns false if the value should be dropped.
+func (c *Connector) transformValue(v pcommon.Value) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeStr:
+		if c.config.DropEmptyStringAttributes && v.Str() == "" {
+			return false
+		}
+	case pcommon.ValueTypeDouble:
+		if c.config.DoubleAttributePrecision != nil && *c.config.DoubleAttributePrecision >= 0 {
+			v.SetDouble(roundDouble(v.Double(), *c.config.DoubleAttributePrecision))
+		}
+	case pcommon.ValueTypeBytes:
+		switch c.config.BytesAttributeEncoding {
+		case "base64":
+			v.SetStr(base64.StdEncoding.EncodeToString(v.Bytes().AsRaw()))
+		case "hex":
+			v.SetStr(hex.EncodeToString(v.Bytes().AsRaw()))
+		case "drop":
+			return false
+		}
+	case pcommon.ValueTypeMap:
+		v.Map().RemoveIf(func(_ string, nested pcommon.Value) bool {
+			return !c.transformValue(nested)
+		})
+		if c.config.DropEmptyCollectionAttributes && v.Map().Len() == 0 {
+			return false
+		}
+	case pcommon.ValueTypeSlice:
+		v.Slice().RemoveIf(func(nested pcommon.Value) bool {
+			return !c.transformValue(nested)
+		})
+		if c.config.DropEmptyCollectionAttributes && v.Slice().Len() == 0 {
+			return false
 		}
 	}
+	return true
+}
 
-	otelSpan.SetAttributes(
-		attribute.Int("total_events_found", totalEvents),
-		attribute.Int("events_processed", processedEvents),
-		attribute.Int("logs_created", logs.LogRecordCount()),
-	)
+// roundDouble rounds a double to the given number of decimal places.
+func roundDouble(value float64, precision int) float64 {
+	scale := math.Pow(10, float64(precision))
+	return math.Round(value*scale) / scale
+}
 
-	return logs
+// startExceptionAggregate turns the log record of a span's first exception event into the record
+// aggregating all of the span's exceptions, using the primary exception message as the body.
+func (c *Connector) startExceptionAggregate(logRecord plog.LogRecord, event ptrace.SpanEvent) {
+	if c.config.BodyMode != "attributes_map" {
+		if message, exists := event.Attributes().Get("exception.message"); exists && message.Type() == pcommon.ValueTypeStr {
+			logRecord.Body().SetStr(message.Str())
+		}
+	}
+	logRecord.Attributes().PutEmptySlice("exception.causes")
 }
 
-// populateLogRecord populates a log record based on a span event.
-func (c *Connector) populateLogRecord(
-	logRecord plog.LogRecord,
-	event ptrace.SpanEvent,
-	span ptrace.Span,
-) {
-	// Default severity
-	severityNumber := plog.SeverityNumberInfo
-	severityText := "info"
-	severityFound := false
+// appendExceptionCause appends the attributes of a further exception event not denied by filter to
+// the "exception.causes" slice of the aggregated exception record. Each cause is charged against
+// the batch attribute budget, and dropped once it is exhausted.
+func appendExceptionCause(logRecord plog.LogRecord, event ptrace.SpanEvent, filter attributeFilter, budget *attributeBudget) {
+	if !budget.take(logRecord) {
+		return
+	}
+	var causes pcommon.Slice
+	if v, exists := logRecord.Attributes().Get("exception.causes"); exists && v.Type() == pcommon.ValueTypeSlice {
+		causes = v.Slice()
+	} else {
+		causes = logRecord.Attributes().PutEmptySlice("exception.causes")
+	}
+	copyUndeniedAttributes(causes.AppendEmpty().SetEmptyMap(), event.Attributes(), filter)
+}
 
-	// 1. Check AttributeMappings for severity (Highest Precedence)
-	if c.config.AttributeMappings.SeverityNumber != "" || c.config.AttributeMappings.SeverityText != "" {
-		if c.config.AttributeMappings.SeverityNumber != "" {
-			if attrValue, exists := event.Attributes().Get(c.config.AttributeMappings.SeverityNumber); exists {
-				if attrValue.Type() == pcommon.ValueTypeInt {
-					severityNumber = plog.SeverityNumber(attrValue.Int())
-					// Derive severity text from the mapped number to keep them in sync
-					severityText = severityNumberToText(severityNumber)
-					severityFound = true
-				}
-			}
+// collapseAttributesToJSON serializes the configured event attributes into a JSON object string
+// attribute, optionally removing the originals from the log record attributes.
+func (c *Connector) collapseAttributesToJSON(dst, eventAttrs pcommon.Map) {
+	collapse := c.config.CollapseAttributesToJSON
+	collapsed := make(map[string]any, len(collapse.SourceKeys))
+	for _, key := range collapse.SourceKeys {
+		if c.eventAttributeFilter.denies(key) {
+			continue
 		}
-		if c.config.AttributeMappings.SeverityText != "" {
-			if attrValue, exists := event.Attributes().Get(c.config.AttributeMappings.SeverityText); exists && attrValue.Type() == pcommon.ValueTypeStr {
-				severityText = attrValue.Str()
-				// If we don't have severity number from attribute mapping, try to parse from text
-				if !severityFound {
-					parsedNumber, parsedText := mapSeverity(severityText)
-					if parsedNumber != plog.SeverityNumberUnspecified {
-						severityNumber = parsedNumber
-						severityText = parsedText
-					}
-				}
-				severityFound = true
-			}
+		if v, exists := eventAttrs.Get(key); exists {
+			collapsed[key] = v.AsRaw()
 		}
 	}
 
-	// 2. Check SeverityAttribute (High Precedence)
-	if !severityFound && c.config.SeverityAttribute != "" {
-		if attrValue, exists := event.Attributes().Get(c.config.SeverityAttribute); exists && attrValue.Type() == pcommon.ValueTypeStr {
-			parsedNumber, parsedText := mapSeverity(attrValue.Str())
-			if parsedNumber != plog.SeverityNumberUnspecified {
-				severityNumber = parsedNumber
-				severityText = parsedText
-				severityFound = true
-			}
-		}
+	encoded, err := json.Marshal(collapsed)
+	if err != nil {
+		c.logger.Debug("Failed to collapse attributes to JSON", zap.Error(err))
+		return
 	}
+	dst.PutStr(collapse.TargetKey, string(encoded))
 
-	// 3. Check SeverityByEventName (Substring Match, Longest Precedence)
-	if !severityFound && len(c.config.SeverityByEventName) > 0 {
-		lowerEventName := strings.ToLower(event.Name())
-		longestMatchKeyLen := 0
-		matchedSeverityText := ""
-
-		for key, configuredSeverity := range c.config.SeverityByEventName {
-			lowerKey := strings.ToLower(key)
-			if strings.Contains(lowerEventName, lowerKey) {
-				if len(key) > longestMatchKeyLen {
-					// Check if the configuredSeverity is valid before accepting it
-					parsedNumber, parsedText := mapSeverity(configuredSeverity)
-					if parsedNumber != plog.SeverityNumberUnspecified {
-						longestMatchKeyLen = len(key)
-						matchedSeverityText = parsedText // Use the canonical text from mapSeverity
-					}
-				}
-			}
+	if collapse.RemoveOriginals {
+		for _, key := range collapse.SourceKeys {
+			dst.Remove(key)
 		}
+	}
+}
 
-		if matchedSeverityText != "" {
-			severityNumber, severityText = mapSeverity(matchedSeverityText) // Remap to get both Number and Text
-			severityFound = true
+// parseStacktrace splits the "exception.stacktrace" attribute into an "exception.stacktrace.frames"
+// slice attribute containing one entry per non-empty line.
+func (c *Connector) parseStacktrace(attrs pcommon.Map) {
+	stacktrace, exists := attrs.Get("exception.stacktrace")
+	if !exists || stacktrace.Type() != pcommon.ValueTypeStr {
+		return
+	}
+
+	frames := attrs.PutEmptySlice("exception.stacktrace.frames")
+	for _, line := range strings.Split(stacktrace.Str(), "\n") {
+		frame := strings.TrimSpace(line)
+		if frame != "" {
+			frames.AppendEmpty().SetStr(frame)
 		}
 	}
 
-	// Set timestamp from event
-	logRecord.SetTimestamp(event.Timestamp())
+	if c.config.DropRawStacktrace {
+		attrs.Remove("exception.stacktrace")
+	}
+}
 
-	// Set observed timestamp to current time
-	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+// extractBodyFields matches the string body against the body extraction pattern and sets an
+// attribute for each named group that participated in the match.
+func (c *Connector) extractBodyFields(logRecord plog.LogRecord) {
+	if logRecord.Body().Type() != pcommon.ValueTypeStr {
+		return
+	}
+	body := logRecord.Body().Str()
+	match := c.bodyExtractPattern.FindStringSubmatchIndex(body)
+	if match == nil {
+		return
+	}
+	for i, name := range c.bodyExtractPattern.SubexpNames() {
+		if name == "" || match[2*i] < 0 {
+			continue
+		}
+		logRecord.Attributes().PutStr(name, body[match[2*i]:match[2*i+1]])
+	}
+}
 
-	// Set the determined severity (or default if not found)
-	logRecord.SetSeverityNumber(severityNumber)
-	logRecord.SetSeverityText(severityText)
+// preserveEventAsMap copies the name, timestamp, attributes not denied by filter and dropped
+// attributes count of an event into dst, keeping the attribute structure as is for round-tripping.
+func preserveEventAsMap(dst pcommon.Map, event ptrace.SpanEvent, filter attributeFilter) {
+	dst.PutStr("name", event.Name())
+	dst.PutInt("time_unix_nano", int64(event.Timestamp()))
+	copyUndeniedAttributes(dst.PutEmptyMap("attributes"), event.Attributes(), filter)
+	dst.PutInt("dropped_attributes_count", int64(event.DroppedAttributesCount()))
+}
 
-	// Set body using attribute mapping or fallback to event name
-	bodySet := false
-	if c.config.AttributeMappings.Body != "" {
-		if attrValue, exists := event.Attributes().Get(c.config.AttributeMappings.Body); exists && attrValue.Type() == pcommon.ValueTypeStr {
-			logRecord.Body().SetStr(attrValue.Str())
-			bodySet = true
+// applySemConvRenames moves the attributes with a legacy key to their semantic-convention key.
+// Attributes already present under the semantic-convention key are kept.
+func applySemConvRenames(attrs pcommon.Map, renames map[string]string) {
+	for legacyKey, key := range renames {
+		v, exists := attrs.Get(legacyKey)
+		if !exists {
+			continue
 		}
+		if _, taken := attrs.Get(key); !taken {
+			v.CopyTo(attrs.PutEmpty(key))
+		}
+		attrs.Remove(legacyKey)
 	}
-	if !bodySet {
-		// Fallback to event name
-		logRecord.Body().SetStr(event.Name())
+}
+
+// renameAttributes moves the attributes under each source key of renames to the target key,
+// overwriting any existing value. All values are taken before any is moved, so renames do not chain;
+// when several sources share a target, the lexically last source wins.
+func renameAttributes(attrs pcommon.Map, renames map[string]string) {
+	sources := make([]string, 0, len(renames))
+	for source := range renames {
+		if _, exists := attrs.Get(source); exists {
+			sources = append(sources, source)
+		}
 	}
+	sort.Strings(sources)
 
-	// Copy event attributes if configured
-	if c.shouldCopyAttributes("event.attributes") {
-		event.Attributes().CopyTo(logRecord.Attributes())
+	values := make([]pcommon.Value, len(sources))
+	for i, source := range sources {
+		v, _ := attrs.Get(source)
+		values[i] = pcommon.NewValueEmpty()
+		v.CopyTo(values[i])
+	}
+	for _, source := range sources {
+		attrs.Remove(source)
 	}
+	for i, source := range sources {
+		values[i].CopyTo(attrs.PutEmpty(renames[source]))
+	}
+}
 
-	// Preserve event name as attribute if configured
-	if c.config.AttributeMappings.EventName != "" {
-		logRecord.Attributes().PutStr(c.config.AttributeMappings.EventName, event.Name())
+// shortenExceptionType stores the last dot-separated segment of the "exception.type" attribute
+// (e.g. "NullPointerException" for "java.lang.NullPointerException") as "error.type.short",
+// keeping the full value.
+func shortenExceptionType(attrs pcommon.Map) {
+	exceptionType, exists := attrs.Get("exception.type")
+	if !exists || exceptionType.Type() != pcommon.ValueTypeStr || exceptionType.Str() == "" {
+		return
 	}
 
-	// Add level attribute if configured and not already present
-	if c.config.AddLevel {
-		// Check if level attribute already exists in log record attributes
-		_, hasLevel := logRecord.Attributes().Get("level")
-		if !hasLevel {
-			// Add level attribute based on severity text
-			logRecord.Attributes().PutStr("level", logRecord.SeverityText())
+	fullType := exceptionType.Str()
+	attrs.PutStr("error.type.short", fullType[strings.LastIndex(fullType, ".")+1:])
+}
+
+// spanNameHash returns the hex-encoded 32-bit FNV-1a hash of a span name.
+func spanNameHash(name string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// recordID returns a deterministic ID for the log record of a span event: the hex-encoded 128-bit
+// FNV-1a hash of the trace ID, span ID, event index and event name.
+func recordID(span ptrace.Span, eventIndex int, eventName string) string {
+	traceID := span.TraceID()
+	spanID := span.SpanID()
+	h := fnv.New128a()
+	_, _ = h.Write(traceID[:])
+	_, _ = h.Write(spanID[:])
+	_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(eventIndex)))
+	_, _ = h.Write([]byte(eventName))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// renderTemplate renders a compiled attribute template against the event, its span and its resource.
+// References to missing attributes render as empty strings.
+func renderTemplate(tmpl config.AttributeTemplate, event ptrace.SpanEvent, span ptrace.Span, resource pcommon.Resource) string {
+	var sb strings.Builder
+	for _, part := range tmpl {
+		if part.Source == "" {
+			sb.WriteString(part.Literal)
+			continue
 		}
-	}
 
-	// Copy span attributes if configured
-	if c.shouldCopyAttributes("span.attributes") {
-		span.Attributes().Range(func(k string, v pcommon.Value) bool {
-			v.CopyTo(logRecord.Attributes().PutEmpty(k))
-			return true
-		})
+		var attrs pcommon.Map
+		switch part.Source {
+		case "resource":
+			attrs = resource.Attributes()
+		case "span":
+			attrs = span.Attributes()
+		case "event":
+			attrs = event.Attributes()
+		}
+		if v, exists := attrs.Get(part.Key); exists {
+			sb.WriteString(v.AsString())
+		}
 	}
+	return sb.String()
+}
 
-	// Add trace and span ID fields if configured
-	if c.config.IncludeSpanContext {
-		logRecord.SetTraceID(span.TraceID())
-		logRecord.SetSpanID(span.SpanID())
+// shouldIncludeSpanContext determines if span context should be injected into the log record for the span.
+func (c *Connector) shouldIncludeSpanContext(span ptrace.Span) bool {
+	if !c.config.IncludeSpanContext {
+		return false
+	}
+	if c.config.SpanContextOnlyIfRemote && !hasRemoteParent(span) {
+		return false
+	}
+	return true
+}
 
-		// Set flags
-		if span.TraceState().AsRaw() != "" {
-			logRecord.Attributes().PutStr("trace.state", span.TraceState().AsRaw())
+// eventSkipsSpanContext determines if span context injection is skipped for the event, either
+// because it opted out through SkipSpanContextAttribute or its name matches SkipSpanContextForPatterns.
+func (c *Connector) eventSkipsSpanContext(event ptrace.SpanEvent) bool {
+	for _, re := range c.skipSpanContextPatterns {
+		if re.MatchString(event.Name()) {
+			return true
 		}
+	}
+	if c.config.SkipSpanContextAttribute == "" {
+		return false
+	}
+	attrValue, exists := event.Attributes().Get(c.config.SkipSpanContextAttribute)
+	return exists && isTruthy(attrValue)
+}
 
-		// Add span name
-		logRecord.Attributes().PutStr("span.name", span.Name())
+// isTruthy reports whether an attribute value represents true: a true bool, a string parsing as
+// true (e.g. "true" or "1"), or a non-zero int.
+func isTruthy(v pcommon.Value) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeBool:
+		return v.Bool()
+	case pcommon.ValueTypeStr:
+		b, err := strconv.ParseBool(v.Str())
+		return err == nil && b
+	case pcommon.ValueTypeInt:
+		return v.Int() != 0
+	}
+	return false
+}
 
-		// Add span kind
-		logRecord.Attributes().PutStr("span.kind", span.Kind().String())
+// hasRemoteParent reports whether the span's parent is remote. The span flags are authoritative
+// when the producer recorded remoteness. Otherwise, since pdata doesn't expose the parent context,
+// SERVER and CONSUMER spans with a parent span ID are assumed to continue a remote trace.
+func hasRemoteParent(span ptrace.Span) bool {
+	if span.Flags()&spanFlagsHasIsRemote != 0 {
+		return span.Flags()&spanFlagsIsRemote != 0
 	}
+	if span.ParentSpanID().IsEmpty() {
+		return false
+	}
+	return span.Kind() == ptrace.SpanKindServer || span.Kind() == ptrace.SpanKindConsumer
 }
 
 // shouldCopyAttributes determines if attributes should be copied from the specified source.
@@ -413,6 +2710,26 @@ func (c *Connector) shouldCopyAttributes(source string) bool {
 	return false
 }
 
+// originalSeverityText returns the severity text read from the event attribute the severity was
+// resolved from, before canonicalization. Returns false if the severity didn't come from a text attribute.
+func (c *Connector) originalSeverityText(event ptrace.SpanEvent, severitySource string) (string, bool) {
+	var key string
+	switch severitySource {
+	case "attribute_mappings":
+		key = c.config.AttributeMappings.SeverityText
+	case "severity_attribute":
+		key = c.config.SeverityAttribute
+	}
+	if key == "" {
+		return "", false
+	}
+	attrValue, exists := event.Attributes().Get(key)
+	if !exists || attrValue.Type() != pcommon.ValueTypeStr {
+		return "", false
+	}
+	return attrValue.Str(), true
+}
+
 // mapSeverity maps a severity string (case-insensitive) to a plog.SeverityNumber and its canonical text.
 // Returns SeverityNumberUnspecified and an empty string if the input is not a valid severity.
 func mapSeverity(severity string) (plog.SeverityNumber, string) {
@@ -443,6 +2760,82 @@ func mapSeverity(severity string) (plog.SeverityNumber, string) {
 	return plog.SeverityNumberUnspecified, ""
 }
 
+// severityNumberFromValue reads a severity number from an int attribute, a string holding an
+// integer (e.g. "9"), or a double, which is truncated. Returns false if the value cannot be parsed.
+func severityNumberFromValue(v pcommon.Value) (plog.SeverityNumber, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return plog.SeverityNumber(v.Int()), true
+	case pcommon.ValueTypeStr:
+		n, err := strconv.Atoi(strings.TrimSpace(v.Str()))
+		if err != nil {
+			return plog.SeverityNumberUnspecified, false
+		}
+		return plog.SeverityNumber(n), true
+	case pcommon.ValueTypeDouble:
+		return plog.SeverityNumber(int64(v.Double())), true
+	default:
+		return plog.SeverityNumberUnspecified, false
+	}
+}
+
+// mapSeverity maps a severity string like the package-level mapSeverity, also accepting the
+// words configured in SeverityAliases.
+func (c *Connector) mapSeverity(severity string) (plog.SeverityNumber, string) {
+	if severityNumber, exists := c.severityAliases[strings.ToLower(severity)]; exists {
+		return severityNumber, severityToTextMap[severityNumber]
+	}
+	return mapSeverity(severity)
+}
+
+// mapAttributeSeverity maps a severity level read from an event attribute, accepting the
+// Log4j/Logback levels when Log4jSeverityMode is set.
+func (c *Connector) mapAttributeSeverity(severity string) (plog.SeverityNumber, string) {
+	if c.config.Log4jSeverityMode {
+		if severityNumber, exists := log4jSeverityMap[strings.ToLower(severity)]; exists {
+			return severityNumber, severityToTextMap[severityNumber]
+		}
+	}
+	return c.mapSeverity(severity)
+}
+
+// spanKindName returns the configuration name of a span kind (e.g. "server").
+func spanKindName(kind ptrace.SpanKind) string {
+	return strings.ToLower(kind.String())
+}
+
+// parseSpanKind returns the span kind with the given configuration name.
+func parseSpanKind(name string) (ptrace.SpanKind, bool) {
+	for _, kind := range []ptrace.SpanKind{
+		ptrace.SpanKindUnspecified,
+		ptrace.SpanKindInternal,
+		ptrace.SpanKindServer,
+		ptrace.SpanKindClient,
+		ptrace.SpanKindProducer,
+		ptrace.SpanKindConsumer,
+	} {
+		if spanKindName(kind) == name {
+			return kind, true
+		}
+	}
+	return ptrace.SpanKindUnspecified, false
+}
+
+// parseStatusCode returns the span status code with the given configuration name ("unset", "ok" or "error").
+func parseStatusCode(name string) (ptrace.StatusCode, bool) {
+	for _, code := range []ptrace.StatusCode{ptrace.StatusCodeUnset, ptrace.StatusCodeOk, ptrace.StatusCodeError} {
+		if strings.ToLower(code.String()) == name {
+			return code, true
+		}
+	}
+	return ptrace.StatusCodeUnset, false
+}
+
+// isErrorSeverity determines if a severity number is in the error range (ERROR to ERROR4).
+func isErrorSeverity(severityNumber plog.SeverityNumber) bool {
+	return severityNumber >= plog.SeverityNumberError && severityNumber <= plog.SeverityNumberError4
+}
+
 // severityNumberToText maps a plog.SeverityNumber to its canonical text representation.
 // Returns "info" as default for unspecified or unknown severity numbers.
 func severityNumberToText(severityNumber plog.SeverityNumber) string {
@@ -5,6 +5,9 @@ package spaneventtologconnector // import "github.com/dev7a/otelcol-con-spaneven
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +21,11 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap/zaptest"
 
 	"github.com/dev7a/otelcol-con-spaneventtolog/spaneventtologconnector/config"
@@ -40,13 +48,14 @@ func TestTracingInstrumentationIntegration(t *testing.T) {
 		},
 	}
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, *cfg, logsSink)
+	connector, err := newConnector(settings, *cfg, logsSink)
+	require.NoError(t, err)
 
 	// Verify tracer is set
 	assert.NotNil(t, connector.tracer, "Tracer should be initialized")
 
 	// Consume traces - this exercises the tracing code paths
-	err := connector.ConsumeTraces(context.Background(), traces)
+	err = connector.ConsumeTraces(context.Background(), traces)
 	assert.NoError(t, err)
 
 	// Verify logs were created (indicating the instrumented methods worked)
@@ -73,13 +82,14 @@ func TestTracingInstrumentationWithError(t *testing.T) {
 		LogAttributesFrom:  []string{"event.attributes"},
 	}
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, *cfg, errorConsumer)
+	connector, err := newConnector(settings, *cfg, errorConsumer)
+	require.NoError(t, err)
 
 	// Verify tracer is set
 	assert.NotNil(t, connector.tracer, "Tracer should be initialized")
 
 	// Consume traces - should return error
-	err := connector.ConsumeTraces(context.Background(), traces)
+	err = connector.ConsumeTraces(context.Background(), traces)
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, assert.AnError)
 }
@@ -103,13 +113,14 @@ func TestTracingInstrumentationNoEvents(t *testing.T) {
 		LogAttributesFrom:  []string{"event.attributes"},
 	}
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, *cfg, logsSink)
+	connector, err := newConnector(settings, *cfg, logsSink)
+	require.NoError(t, err)
 
 	// Verify tracer is set
 	assert.NotNil(t, connector.tracer, "Tracer should be initialized")
 
 	// Consume traces
-	err := connector.ConsumeTraces(context.Background(), traces)
+	err = connector.ConsumeTraces(context.Background(), traces)
 	assert.NoError(t, err)
 
 	// Verify no logs consumer call was made (0 logs)
@@ -123,7 +134,8 @@ func TestConnectorTracerInitialization(t *testing.T) {
 	logsSink := new(consumertest.LogsSink)
 
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, *cfg, logsSink)
+	connector, err := newConnector(settings, *cfg, logsSink)
+	require.NoError(t, err)
 
 	// Verify tracer is initialized with correct name
 	assert.NotNil(t, connector.tracer, "Tracer should be initialized")
@@ -162,6 +174,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, []string{"event.attributes", "resource.attributes"}, cfgTyped.LogAttributesFrom, "default LogAttributesFrom should include event.attributes and resource.attributes")
 	assert.Equal(t, map[string]string{"exception": "error"}, cfgTyped.SeverityByEventName, "default SeverityByEventName should map exception to error")
 	assert.False(t, cfgTyped.AddLevel, "default AddLevel should be false")
+	assert.Nil(t, cfgTyped.DoubleAttributePrecision, "default DoubleAttributePrecision should disable rounding")
 }
 
 func TestCreateTracesToLogs(t *testing.T) {
@@ -247,10 +260,11 @@ func TestNoEmptyLogsWhenNoMatchingEvents(t *testing.T) {
 		IncludeEventNames: []string{"nonexistent_event"}, // This won't match any events in our test data
 	}
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, *cfg, logsSink)
+	connector, err := newConnector(settings, *cfg, logsSink)
+	require.NoError(t, err)
 
 	// Consume traces
-	err := connector.ConsumeTraces(context.Background(), traces)
+	err = connector.ConsumeTraces(context.Background(), traces)
 
 	// Should not return an error
 	assert.NoError(t, err)
@@ -369,10 +383,11 @@ func TestAttributeMappings(t *testing.T) {
 
 			// Create connector with the test configuration
 			settings := createTestConnectorSettings(t)
-			connector := newConnector(settings, tt.config, logsSink)
+			connector, err := newConnector(settings, tt.config, logsSink)
+			require.NoError(t, err)
 
 			// Consume traces
-			err := connector.ConsumeTraces(context.Background(), traces)
+			err = connector.ConsumeTraces(context.Background(), traces)
 			assert.NoError(t, err)
 
 			// Verify logs were created
@@ -422,10 +437,11 @@ func TestAttributeMappingsPrecedence(t *testing.T) {
 		},
 	}
 	settings := createTestConnectorSettings(t)
-	connector := newConnector(settings, cfg, logsSink)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
 
 	// Consume traces
-	err := connector.ConsumeTraces(context.Background(), traces)
+	err = connector.ConsumeTraces(context.Background(), traces)
 	assert.NoError(t, err)
 
 	// Verify logs were created
@@ -559,3 +575,4990 @@ func TestMapSeverity(t *testing.T) {
 		})
 	}
 }
+
+// TestScopePerEventName tests that log records are grouped into one scope per event name
+func TestScopePerEventName(t *testing.T) {
+	traces := createTestTraces()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	secondException := span.Events().AppendEmpty()
+	secondException.SetName("exception")
+	secondException.Attributes().PutStr("exception.type", "IllegalStateException")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		ScopePerEventName: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Equal(t, 1, len(allLogs), "Expected logs to be created")
+	require.Equal(t, 3, allLogs[0].LogRecordCount(), "Expected 3 log records")
+
+	scopeNames := map[string]struct{}{}
+	rls := allLogs[0].ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			scopeNames[sl.Scope().Name()] = struct{}{}
+			assert.Empty(t, sl.Scope().Version(), "Event name scopes should not inherit the source scope version")
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				// The body falls back to the event name, so it must match the scope name
+				assert.Equal(t, sl.Scope().Name(), sl.LogRecords().At(k).Body().Str(), "Log record placed in the wrong scope")
+			}
+		}
+	}
+	assert.Equal(t, map[string]struct{}{"exception": {}, "custom": {}}, scopeNames, "Expected one scope per distinct event name")
+}
+
+// TestIncludeStatusCode tests that the span status code and message are added as attributes when configured
+func TestIncludeStatusCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    ptrace.StatusCode
+		statusMessage string
+		expectedCode  string
+	}{
+		{"Unset status", ptrace.StatusCodeUnset, "", "Unset"},
+		{"Ok status", ptrace.StatusCodeOk, "", "Ok"},
+		{"Error status with message", ptrace.StatusCodeError, "connection refused", "Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.Status().SetCode(tt.statusCode)
+			span.Status().SetMessage(tt.statusMessage)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeStatusCode: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			allLogs := logsSink.AllLogs()
+			require.Equal(t, 1, len(allLogs), "Expected logs to be created")
+			logRecord := allLogs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+
+			statusCode, exists := logRecord.Attributes().Get("span.status_code")
+			require.True(t, exists, "Expected span.status_code attribute to exist")
+			assert.Equal(t, tt.expectedCode, statusCode.Str())
+
+			statusMessage, exists := logRecord.Attributes().Get("span.status_message")
+			require.Equal(t, tt.statusMessage != "", exists, "Unexpected presence of span.status_message")
+			if exists {
+				assert.Equal(t, tt.statusMessage, statusMessage.Str())
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		traces := createTestTracesWithStructuredEvent()
+		logsSink := new(consumertest.LogsSink)
+		settings := createTestConnectorSettings(t)
+		connector, err := newConnector(settings, config.Config{}, logsSink)
+		require.NoError(t, err)
+
+		err = connector.ConsumeTraces(context.Background(), traces)
+		assert.NoError(t, err)
+
+		logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		_, exists := logRecord.Attributes().Get("span.status_code")
+		assert.False(t, exists, "span.status_code should not be set when disabled")
+	})
+}
+
+// TestIncludeCollectorHostname tests that the hostname resolved at start is stamped on each log record
+func TestIncludeCollectorHostname(t *testing.T) {
+	expectedHostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeCollectorHostname: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+	require.NoError(t, connector.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, connector.Shutdown(context.Background()))
+	}()
+
+	err = connector.ConsumeTraces(context.Background(), createTestTracesWithEventNames("first", "second"))
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	for _, logRecord := range logRecords {
+		hostname, exists := logRecord.Attributes().Get("collector.hostname")
+		require.True(t, exists, "Expected collector.hostname attribute to exist")
+		assert.NotEmpty(t, hostname.Str())
+		assert.Equal(t, expectedHostname, hostname.Str())
+	}
+}
+
+// TestParseStacktrace tests that exception stacktraces are split into frames when configured
+func TestParseStacktrace(t *testing.T) {
+	const stacktrace = "java.lang.NullPointerException: Object was null\n" +
+		"\tat com.example.Test.method(Test.java:42)\r\n" +
+		"\tat com.example.Test.main(Test.java:10)\n\n"
+
+	tests := []struct {
+		name              string
+		dropRawStacktrace bool
+	}{
+		{"Keep raw stacktrace", false},
+		{"Drop raw stacktrace", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTraces()
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutStr("exception.stacktrace", stacktrace)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeEventNames: []string{"exception"},
+				LogAttributesFrom: []string{"event.attributes"},
+				ParseStacktrace:   true,
+				DropRawStacktrace: tt.dropRawStacktrace,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			allLogs := logsSink.AllLogs()
+			require.Equal(t, 1, len(allLogs), "Expected logs to be created")
+			logRecord := allLogs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+
+			frames, exists := logRecord.Attributes().Get("exception.stacktrace.frames")
+			require.True(t, exists, "Expected exception.stacktrace.frames attribute to exist")
+			require.Equal(t, pcommon.ValueTypeSlice, frames.Type())
+			assert.Equal(t, []any{
+				"java.lang.NullPointerException: Object was null",
+				"at com.example.Test.method(Test.java:42)",
+				"at com.example.Test.main(Test.java:10)",
+			}, frames.Slice().AsRaw())
+
+			_, hasRaw := logRecord.Attributes().Get("exception.stacktrace")
+			assert.Equal(t, !tt.dropRawStacktrace, hasRaw, "Unexpected presence of raw stacktrace")
+		})
+	}
+}
+
+// TestAnnotateSourceScope tests that the source instrumentation scope name is recorded when configured
+func TestAnnotateSourceScope(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AnnotateSourceScope: true,
+		ScopePerEventName:   true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Equal(t, 1, len(allLogs), "Expected logs to be created")
+	scopeLogs := allLogs[0].ResourceLogs().At(0).ScopeLogs().At(0)
+
+	// The output scope is named after the event, but the annotation keeps the source scope
+	assert.Equal(t, "backend.db.write_item.success", scopeLogs.Scope().Name())
+	sourceScope, exists := scopeLogs.LogRecords().At(0).Attributes().Get("spaneventtolog.source_scope")
+	require.True(t, exists, "Expected spaneventtolog.source_scope attribute to exist")
+	assert.Equal(t, "test-scope", sourceScope.Str())
+}
+
+// TestIncludeEventNamePatterns tests regex event name inclusion with negated patterns
+func TestIncludeEventNamePatterns(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         config.Config
+		expectedBodies []string
+	}{
+		{
+			name: "Negation excludes a subset of included events",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{`http\..*`, "!http.healthz"},
+			},
+			expectedBodies: []string{"http.request", "http.response"},
+		},
+		{
+			name: "Negation overrides exact event names",
+			config: config.Config{
+				IncludeEventNames:        []string{"http.healthz", "db.query"},
+				IncludeEventNamePatterns: []string{"!http.healthz"},
+			},
+			expectedBodies: []string{"db.query"},
+		},
+		{
+			name: "Only negations include everything else",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{"!http.healthz"},
+			},
+			expectedBodies: []string{"http.request", "http.response", "db.query"},
+		},
+		{
+			name: "Patterns must match the entire event name",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{"http"},
+			},
+			expectedBodies: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("http.request", "http.healthz", "http.response", "db.query")
+
+			logsSink := new(consumertest.LogsSink)
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, tt.config, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestIncludeEventNamesAndPatterns tests that an event passes if it matches the exact set or any pattern
+func TestIncludeEventNamesAndPatterns(t *testing.T) {
+	traces := createTestTracesWithEventNames("db.query.users", "db.query.orders", "db.connect", "cache.hit", "exception")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEventNames:        []string{"exception"},
+		IncludeEventNamePatterns: []string{`db\.query\..*`},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"db.query.users", "db.query.orders", "exception"}, collectLogBodies(logsSink))
+}
+
+func TestIncludeEventNameGlobs(t *testing.T) {
+	traces := createTestTracesWithEventNames("order.created", "order.shipped", "order.item.added", "payment.failed", "cache.hit", "exception")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEventNames:        []string{"exception"},
+		IncludeEventNamePatterns: []string{`payment\..*`},
+		IncludeEventNameGlobs:    []string{"order.*"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	// Globs, patterns and exact names are unioned
+	assert.Equal(t, []string{"order.created", "order.shipped", "order.item.added", "payment.failed", "exception"}, collectLogBodies(logsSink))
+}
+
+// TestConfigValidate tests the connector configuration validation
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      config.Config
+		expectedErr string
+	}{
+		{
+			name:   "Empty config",
+			config: config.Config{},
+		},
+		{
+			name: "Valid event name patterns",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{`http\..*`, "!http.healthz"},
+			},
+		},
+		{
+			name: "Invalid event name pattern",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{"!http.(healthz"},
+			},
+			expectedErr: "invalid include event name pattern",
+		},
+		{
+			name: "Invalid event name glob",
+			config: config.Config{
+				IncludeEventNameGlobs: []string{"order.[created"},
+			},
+			expectedErr: "invalid include event name glob",
+		},
+		{
+			name: "Invalid attribute presence severity",
+			config: config.Config{
+				SeverityByAttributePresence: map[string]string{"error.message": "loud"},
+			},
+			expectedErr: "invalid severity level for attribute error.message: loud",
+		},
+		{
+			name: "Valid routing attribute template",
+			config: config.Config{
+				RoutingAttributes: map[string]string{"tenant": "static-{resource:tenant.id}"},
+			},
+		},
+		{
+			name: "Unclosed routing attribute template reference",
+			config: config.Config{
+				RoutingAttributes: map[string]string{"tenant": "{resource:tenant.id"},
+			},
+			expectedErr: "unclosed reference",
+		},
+		{
+			name: "Unknown routing attribute template source",
+			config: config.Config{
+				RoutingAttributes: map[string]string{"tenant": "{scope:tenant.id}"},
+			},
+			expectedErr: "invalid reference source",
+		},
+		{
+			name: "Routing attribute template reference without key",
+			config: config.Config{
+				RoutingAttributes: map[string]string{"tenant": "{resource}"},
+			},
+			expectedErr: "expected {source:key}",
+		},
+		{
+			name: "Valid body mode",
+			config: config.Config{
+				BodyMode: "attributes_map",
+			},
+		},
+		{
+			name: "Invalid body mode",
+			config: config.Config{
+				BodyMode: "attributes",
+			},
+			expectedErr: "invalid body mode: attributes",
+		},
+		{
+			name: "Invalid body extract pattern",
+			config: config.Config{
+				BodyExtractPattern: `user=(?P<user>\S+`,
+			},
+			expectedErr: "invalid body extract pattern",
+		},
+		{
+			name: "Body extract pattern without named groups",
+			config: config.Config{
+				BodyExtractPattern: `user=(\S+)`,
+			},
+			expectedErr: "has no named capture groups",
+		},
+		{
+			name: "Invalid semconv mode",
+			config: config.Config{
+				SemConvMode: "grpc",
+			},
+			expectedErr: "invalid semconv mode: grpc",
+		},
+		{
+			name: "Invalid attribute rename pattern",
+			config: config.Config{
+				AttributeRenameRules: []config.AttributeRenameRule{{Pattern: `^db\.(.*$`, Replacement: "database.$1"}},
+			},
+			expectedErr: "invalid attribute rename pattern",
+		},
+		{
+			name: "Empty attribute rename target",
+			config: config.Config{
+				AttributeRenames: map[string]string{"event.body": ""},
+			},
+			expectedErr: "attribute rename target for event.body must not be empty",
+		},
+		{
+			name: "Denylisted attribute rename target",
+			config: config.Config{
+				EventAttributeDenylist: []string{"secret"},
+				AttributeRenames:       map[string]string{"ok": "secret"},
+			},
+			expectedErr: "attribute rename target secret for ok is denylisted",
+		},
+		{
+			name: "Invalid severity for unmatched events",
+			config: config.Config{
+				SeverityForUnmatchedEvents: "verbose",
+			},
+			expectedErr: "invalid severity level for unmatched events: verbose",
+		},
+		{
+			name: "Valid timestamp sources",
+			config: config.Config{
+				TimestampSources: []string{"attribute:log.time", "event_time", "span_start", "span_end", "now"},
+			},
+		},
+		{
+			name: "Invalid timestamp source",
+			config: config.Config{
+				TimestampSources: []string{"event_time", "observed"},
+			},
+			expectedErr: "invalid timestamp source: observed",
+		},
+		{
+			name: "Timestamp attribute source without key",
+			config: config.Config{
+				TimestampSources: []string{"attribute:"},
+			},
+			expectedErr: "invalid timestamp source: attribute:",
+		},
+		{
+			name: "Numeric attribute filter with min greater than max",
+			config: config.Config{
+				NumericAttributeFilters: []config.NumericAttributeFilter{{Key: "duration_ms", Min: 5000, Max: 100}},
+			},
+			expectedErr: "min 5000 is greater than max 100",
+		},
+		{
+			name: "Numeric attribute filter without key",
+			config: config.Config{
+				NumericAttributeFilters: []config.NumericAttributeFilter{{Min: 1, Max: 2}},
+			},
+			expectedErr: "numeric attribute filter key must not be empty",
+		},
+		{
+			name: "Invalid bytes attribute encoding",
+			config: config.Config{
+				BytesAttributeEncoding: "base32",
+			},
+			expectedErr: "invalid bytes attribute encoding: base32",
+		},
+		{
+			name: "Invalid body bytes encoding",
+			config: config.Config{
+				BodyBytesEncoding: "raw",
+			},
+			expectedErr: "invalid body bytes encoding: raw",
+		},
+		{
+			name: "Invalid observed timestamp",
+			config: config.Config{
+				ObservedTimestamp: "span_start",
+			},
+			expectedErr: "invalid observed timestamp: span_start",
+		},
+		{
+			name: "Invalid span duration unit",
+			config: config.Config{
+				SpanDurationUnit: "us",
+			},
+			expectedErr: "invalid span duration unit: us",
+		},
+		{
+			name: "Invalid filter condition",
+			config: config.Config{
+				FilterConditions: []string{`name ==`},
+			},
+			expectedErr: "invalid filter conditions",
+		},
+		{
+			name: "Invalid transform statement",
+			config: config.Config{
+				TransformStatements: []string{`delete_key(attributes)`},
+			},
+			expectedErr: "invalid transform statements",
+		},
+		{
+			name: "Invalid event time",
+			config: config.Config{
+				MinEventTime: "2024-05-01",
+			},
+			expectedErr: `invalid event time "2024-05-01"`,
+		},
+		{
+			name: "Min event time after max event time",
+			config: config.Config{
+				MinEventTime: "2024-05-02T00:00:00Z",
+				MaxEventTime: "2024-05-01T00:00:00Z",
+			},
+			expectedErr: "min event time 2024-05-02T00:00:00Z must not be after max event time 2024-05-01T00:00:00Z",
+		},
+		{
+			name: "Correlation key without attribute",
+			config: config.Config{
+				CorrelationKey: config.CorrelationKey{Template: "{resource:service.name}"},
+			},
+			expectedErr: "correlation key attribute must be set",
+		},
+		{
+			name: "Invalid correlation key template",
+			config: config.Config{
+				CorrelationKey: config.CorrelationKey{Attribute: "correlation.key", Template: "{resource:service.name"},
+			},
+			expectedErr: "invalid correlation key template",
+		},
+		{
+			name: "Negative max key depth",
+			config: config.Config{
+				MaxKeyDepth: -1,
+			},
+			expectedErr: "max key depth must not be negative: -1",
+		},
+		{
+			name: "Negative error escalation threshold",
+			config: config.Config{
+				ErrorEscalationThreshold: -1,
+			},
+			expectedErr: "error escalation threshold must not be negative: -1",
+		},
+		{
+			name: "Collapse attributes to json without target key",
+			config: config.Config{
+				CollapseAttributesToJSON: config.CollapseAttributesToJSON{SourceKeys: []string{"user.id"}},
+			},
+			expectedErr: "collapse attributes to json target key must be set",
+		},
+		{
+			name: "Numeric threshold rules without attribute",
+			config: config.Config{
+				SeverityByNumericThreshold: config.SeverityByNumericThreshold{
+					Rules: []config.NumericThresholdRule{{Threshold: 1000, Severity: "warn"}},
+				},
+			},
+			expectedErr: "severity by numeric threshold attribute must be set",
+		},
+		{
+			name: "Invalid numeric threshold severity",
+			config: config.Config{
+				SeverityByNumericThreshold: config.SeverityByNumericThreshold{
+					Attribute: "response_time_ms",
+					Rules:     []config.NumericThresholdRule{{Threshold: 1000, Severity: "loud"}},
+				},
+			},
+			expectedErr: "invalid severity level for numeric threshold 1000: loud",
+		},
+		{
+			name: "Invalid skip span context pattern",
+			config: config.Config{
+				SkipSpanContextForPatterns: []string{"order.(["},
+			},
+			expectedErr: "invalid skip span context pattern",
+		},
+		{
+			name: "Invalid span name pattern",
+			config: config.Config{
+				SpanNamePatterns: []string{"GET /api/(["},
+			},
+			expectedErr: "invalid span name pattern",
+		},
+		{
+			name: "Schema version attribute without version",
+			config: config.Config{
+				SchemaVersionAttribute: "spaneventtolog.schema_version",
+			},
+			expectedErr: "schema version must be set",
+		},
+		{
+			name: "Invalid span kind for severity",
+			config: config.Config{
+				SeverityBySpanKind: map[string]string{"gateway": "warn"},
+			},
+			expectedErr: "invalid span kind: gateway",
+		},
+		{
+			name: "Invalid severity for span kind",
+			config: config.Config{
+				SeverityBySpanKind: map[string]string{"producer": "loud"},
+			},
+			expectedErr: "invalid severity level for span kind producer: loud",
+		},
+		{
+			name: "Event name both included and excluded",
+			config: config.Config{
+				IncludeEventNames: []string{"exception", "cache.hit"},
+				ExcludeEventNames: []string{"cache.hit"},
+			},
+			expectedErr: "event name cache.hit is both included and excluded",
+		},
+		{
+			name: "Invalid included span kind",
+			config: config.Config{
+				IncludeSpanKinds: []string{"server", "gateway"},
+			},
+			expectedErr: "invalid span kind: gateway",
+		},
+		{
+			name: "Invalid included span status",
+			config: config.Config{
+				IncludeSpanStatuses: []string{"failed"},
+			},
+			expectedErr: "invalid span status: failed",
+		},
+		{
+			name: "Negative max total attributes",
+			config: config.Config{
+				MaxTotalAttributes: -1,
+			},
+			expectedErr: "max total attributes must not be negative: -1",
+		},
+		{
+			name: "Invalid severity alias target",
+			config: config.Config{
+				SeverityAliases: map[string]string{"notice": "loud"},
+			},
+			expectedErr: "invalid severity alias target for notice: loud",
+		},
+		{
+			name: "Unspecified severity alias target",
+			config: config.Config{
+				SeverityAliases: map[string]string{"notice": "unspecified"},
+			},
+			expectedErr: "invalid severity alias target for notice: unspecified",
+		},
+		{
+			name: "Invalid severity to downgrade",
+			config: config.Config{
+				DowngradeSeverities: map[string]string{"critical": "error"},
+			},
+			expectedErr: "invalid severity level to downgrade: critical",
+		},
+		{
+			name: "Invalid severity downgrade target",
+			config: config.Config{
+				DowngradeSeverities: map[string]string{"fatal": "quiet"},
+			},
+			expectedErr: "invalid severity downgrade target for fatal: quiet",
+		},
+		{
+			name: "Unspecified severity to downgrade",
+			config: config.Config{
+				DowngradeSeverities: map[string]string{"unspecified": "info"},
+			},
+			expectedErr: "invalid severity level to downgrade: unspecified",
+		},
+		{
+			name: "Unspecified severity downgrade target",
+			config: config.Config{
+				DowngradeSeverities: map[string]string{"fatal": "unspecified"},
+			},
+			expectedErr: "invalid severity downgrade target for fatal: unspecified",
+		},
+		{
+			name: "Invalid default severity",
+			config: config.Config{
+				DefaultSeverity: "loud",
+			},
+			expectedErr: "invalid default severity level: loud",
+		},
+		{
+			name: "Heartbeat metric without interval",
+			config: config.Config{
+				HeartbeatMetric: true,
+			},
+			expectedErr: "heartbeat interval must be positive when the heartbeat metric is enabled: 0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+// createTestTracesWithEventNames creates test traces with a single span carrying one event per given name
+func createTestTracesWithEventNames(names ...string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr("service.name", "test-service")
+	scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+	scopeSpans.Scope().SetName("test-scope")
+
+	span := scopeSpans.Spans().AppendEmpty()
+	span.SetName("test-span")
+	span.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	for _, name := range names {
+		event := span.Events().AppendEmpty()
+		event.SetName(name)
+		event.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	}
+
+	return traces
+}
+
+// collectLogBodies returns the string bodies of all log records received by the sink, in order
+func collectLogBodies(logsSink *consumertest.LogsSink) []string {
+	var bodies []string
+	for _, logRecord := range collectLogRecords(logsSink) {
+		bodies = append(bodies, logRecord.Body().Str())
+	}
+	return bodies
+}
+
+// collectLogRecords returns all log records received by the sink, in order
+func collectLogRecords(logsSink *consumertest.LogsSink) []plog.LogRecord {
+	var logRecords []plog.LogRecord
+	for _, logs := range logsSink.AllLogs() {
+		for i := 0; i < logs.ResourceLogs().Len(); i++ {
+			sls := logs.ResourceLogs().At(i).ScopeLogs()
+			for j := 0; j < sls.Len(); j++ {
+				lrs := sls.At(j).LogRecords()
+				for k := 0; k < lrs.Len(); k++ {
+					logRecords = append(logRecords, lrs.At(k))
+				}
+			}
+		}
+	}
+	return logRecords
+}
+
+// TestSeverityByAttributePresence tests severity inference from the presence of event attribute keys
+func TestSeverityByAttributePresence(t *testing.T) {
+	tests := []struct {
+		name                   string
+		attributes             map[string]any
+		config                 config.Config
+		expectedSeverityNumber plog.SeverityNumber
+		expectedSeverityText   string
+	}{
+		{
+			name:       "Present key yields mapped severity",
+			attributes: map[string]any{"error.message": "connection refused"},
+			config: config.Config{
+				SeverityByAttributePresence: map[string]string{"error.message": "error"},
+			},
+			expectedSeverityNumber: plog.SeverityNumberError,
+			expectedSeverityText:   "error",
+		},
+		{
+			name:       "Missing key keeps default severity",
+			attributes: map[string]any{"message": "all good"},
+			config: config.Config{
+				SeverityByAttributePresence: map[string]string{"error.message": "error"},
+			},
+			expectedSeverityNumber: plog.SeverityNumberInfo,
+			expectedSeverityText:   "info",
+		},
+		{
+			name:       "First present key in event order wins",
+			attributes: map[string]any{"warning.message": "slow", "error.message": "failed"},
+			config: config.Config{
+				SeverityByAttributePresence: map[string]string{"error.message": "error", "warning.message": "warn"},
+			},
+			expectedSeverityNumber: plog.SeverityNumberWarn,
+			expectedSeverityText:   "warn",
+		},
+		{
+			name:       "Takes precedence over event name mapping",
+			attributes: map[string]any{"error.message": "failed"},
+			config: config.Config{
+				SeverityByAttributePresence: map[string]string{"error.message": "error"},
+				SeverityByEventName:         map[string]string{"db": "debug"},
+			},
+			expectedSeverityNumber: plog.SeverityNumberError,
+			expectedSeverityText:   "error",
+		},
+		{
+			name:       "Severity attribute takes precedence",
+			attributes: map[string]any{"error.message": "failed", "log.level": "warn"},
+			config: config.Config{
+				SeverityAttribute:           "log.level",
+				SeverityByAttributePresence: map[string]string{"error.message": "error"},
+			},
+			expectedSeverityNumber: plog.SeverityNumberWarn,
+			expectedSeverityText:   "warn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("db.query")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			// Insert in a fixed order so that "first present key" is deterministic
+			for _, key := range []string{"warning.message", "error.message", "message", "log.level"} {
+				if v, ok := tt.attributes[key]; ok {
+					event.Attributes().PutStr(key, v.(string))
+				}
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, tt.config, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			assert.Equal(t, tt.expectedSeverityNumber, logRecord.SeverityNumber(), "Severity number mismatch")
+			assert.Equal(t, tt.expectedSeverityText, logRecord.SeverityText(), "Severity text mismatch")
+		})
+	}
+}
+
+// TestIncludeServiceVersion tests that service identity is copied from the resource onto each log record
+func TestIncludeServiceVersion(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	resource := traces.ResourceSpans().At(0).Resource()
+	resource.Attributes().PutStr("service.version", "1.2.3")
+	resource.Attributes().PutStr("deployment.environment", "prod")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:     []string{"event.attributes"},
+		IncludeServiceVersion: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	resourceLogs := logsSink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, 0, resourceLogs.Resource().Attributes().Len(), "Resource attributes should not be copied")
+
+	logRecord := resourceLogs.ScopeLogs().At(0).LogRecords().At(0)
+	serviceName, exists := logRecord.Attributes().Get("service.name")
+	require.True(t, exists, "Expected service.name attribute to exist")
+	assert.Equal(t, "test-service", serviceName.Str())
+	serviceVersion, exists := logRecord.Attributes().Get("service.version")
+	require.True(t, exists, "Expected service.version attribute to exist")
+	assert.Equal(t, "1.2.3", serviceVersion.Str())
+	_, exists = logRecord.Attributes().Get("deployment.environment")
+	assert.False(t, exists, "Other resource attributes should not be copied")
+}
+
+// TestDedupeWithinBatch tests that identical records produced within a batch are emitted once
+func TestDedupeWithinBatch(t *testing.T) {
+	createRetriedTraces := func() ptrace.Traces {
+		traces := ptrace.NewTraces()
+		spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for i, name := range []string{"payment.failed", "payment.failed", "payment.succeeded"} {
+			span := spans.AppendEmpty()
+			span.SetName("charge")
+			span.SetTraceID(pcommon.TraceID([16]byte{1}))
+			span.SetSpanID(pcommon.SpanID([8]byte{byte(i + 1)}))
+			event := span.Events().AppendEmpty()
+			event.SetName(name)
+			event.SetTimestamp(pcommon.Timestamp(i + 1))
+			event.Attributes().PutStr("payment.id", "p-1")
+		}
+		return traces
+	}
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		uniqueAttrs    bool
+		expectedBodies []string
+	}{
+		{
+			name:           "Enabled",
+			enabled:        true,
+			expectedBodies: []string{"payment.failed", "payment.succeeded"},
+		},
+		{
+			name:           "Per-record unique attributes are not compared",
+			enabled:        true,
+			uniqueAttrs:    true,
+			expectedBodies: []string{"payment.failed", "payment.succeeded"},
+		},
+		{
+			name:           "Disabled",
+			enabled:        false,
+			expectedBodies: []string{"payment.failed", "payment.failed", "payment.succeeded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom: []string{"event.attributes"},
+				DedupeWithinBatch: tt.enabled,
+			}
+			if tt.uniqueAttrs {
+				cfg.SequenceAttribute = "seq"
+				cfg.IncludeEmissionSequence = true
+				cfg.GenerateRecordID = true
+				cfg.AnnotateSourceIndex = true
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), createRetriedTraces())
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+			if tt.uniqueAttrs {
+				// The kept records retain their own sequence numbers
+				logRecords := collectLogRecords(logsSink)
+				for i, expected := range []int64{0, 2} {
+					seq, exists := logRecords[i].Attributes().Get("seq")
+					require.True(t, exists)
+					assert.Equal(t, expected, seq.Int())
+				}
+			}
+		})
+	}
+}
+
+// TestStaticResource tests that all log records are attributed to the configured static resource in a single ResourceLogs
+func TestStaticResource(t *testing.T) {
+	traces := ptrace.NewTraces()
+	for _, service := range []string{"checkout", "payments"} {
+		resourceSpans := traces.ResourceSpans().AppendEmpty()
+		resourceSpans.Resource().Attributes().PutStr("service.name", service)
+		resourceSpans.SetSchemaUrl("https://opentelemetry.io/schemas/1.26.0")
+		event := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events().AppendEmpty()
+		event.SetName(service + ".done")
+	}
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"resource.attributes"},
+		StaticResource: map[string]string{
+			"service.name":           "spaneventtolog",
+			"deployment.environment": "prod",
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logs := logsSink.AllLogs()[0]
+	require.Equal(t, 1, logs.ResourceLogs().Len(), "Expected a single ResourceLogs")
+	resourceLogs := logs.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{
+		"service.name":           "spaneventtolog",
+		"deployment.environment": "prod",
+	}, resourceLogs.Resource().Attributes().AsRaw())
+	assert.Empty(t, resourceLogs.SchemaUrl())
+	assert.Equal(t, []string{"checkout.done", "payments.done"}, collectLogBodies(logsSink))
+}
+
+// TestInjectConnectorScope tests that each ResourceLogs carries a connector scope with a single marker record
+func TestInjectConnectorScope(t *testing.T) {
+	traces := ptrace.NewTraces()
+	for _, service := range []string{"checkout", "payments"} {
+		resourceSpans := traces.ResourceSpans().AppendEmpty()
+		resourceSpans.Resource().Attributes().PutStr("service.name", service)
+		scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+		scopeSpans.Scope().SetName("test-scope")
+		events := scopeSpans.Spans().AppendEmpty().Events()
+		events.AppendEmpty().SetName(service + ".started")
+		events.AppendEmpty().SetName(service + ".done")
+	}
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:    []string{"resource.attributes"},
+		InjectConnectorScope: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	rls := logsSink.AllLogs()[0].ResourceLogs()
+	require.Equal(t, 2, rls.Len())
+	for i := 0; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		require.Equal(t, 2, sls.Len(), "Expected the event scope and the connector scope")
+		assert.Equal(t, "test-scope", sls.At(0).Scope().Name())
+		assert.Equal(t, 2, sls.At(0).LogRecords().Len(), "Event records should stay in their own scope")
+
+		connectorScope := sls.At(1)
+		assert.Equal(t, "spaneventtolog", connectorScope.Scope().Name())
+		require.Equal(t, 1, connectorScope.LogRecords().Len())
+		marker := connectorScope.LogRecords().At(0)
+		assert.Equal(t, map[string]any{
+			"spaneventtolog.marker":       true,
+			"spaneventtolog.component_id": "spaneventtolog/test",
+		}, marker.Attributes().AsRaw())
+		assert.Equal(t, "produced by spaneventtolog/test", marker.Body().Str())
+	}
+}
+
+// TestAttributePrefixes tests that span and resource attribute keys are prefixed while event attribute keys are not
+func TestAttributePrefixes(t *testing.T) {
+	traces := createTestTracesWithEventNames("request")
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().PutStr("http.method", "POST")
+	span.Events().At(0).Attributes().PutStr("http.method", "GET")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:       []string{"event.attributes", "span.attributes"},
+		IncludeServiceVersion:   true,
+		SpanAttributePrefix:     "span.",
+		ResourceAttributePrefix: "resource.",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	attrs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	expected := map[string]string{
+		"http.method":           "GET",
+		"span.http.method":      "POST",
+		"resource.service.name": "test-service",
+	}
+	for key, value := range expected {
+		attr, exists := attrs.Get(key)
+		require.True(t, exists, "Expected %s attribute to exist", key)
+		assert.Equal(t, value, attr.Str())
+	}
+	_, exists := attrs.Get("service.name")
+	assert.False(t, exists, "Resource attributes should only be copied under the prefix")
+}
+
+// TestDefaultBody tests that the configured default body is used for unnamed events without a body attribute
+func TestDefaultBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		eventName    string
+		bodyAttr     string
+		expectedBody string
+	}{
+		{"Unnamed event without body attribute", "", "", "unnamed span event"},
+		{"Unnamed event with body attribute", "", "explicit body", "explicit body"},
+		{"Named event without body attribute", "db.query", "", "db.query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames(tt.eventName)
+			if tt.bodyAttr != "" {
+				event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+				event.Attributes().PutStr("event.body", tt.bodyAttr)
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				DefaultBody: "unnamed span event",
+				AttributeMappings: config.AttributeMappings{
+					Body: "event.body",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []string{tt.expectedBody}, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestRoutingAttributes tests that routing attributes are rendered from resource, span and event attributes
+func TestRoutingAttributes(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	resourceSpans := traces.ResourceSpans().At(0)
+	resourceSpans.Resource().Attributes().PutStr("tenant.id", "acme")
+	span := resourceSpans.ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().PutStr("http.route", "/orders")
+	span.Events().At(0).Attributes().PutInt("shard", 7)
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		RoutingAttributes: map[string]string{
+			"tenant":  "{resource:tenant.id}",
+			"route":   "tenant-{resource:tenant.id}/{span:http.route}/shard-{event:shard}",
+			"missing": "x{event:does.not.exist}y",
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	expected := map[string]string{
+		"tenant":  "acme",
+		"route":   "tenant-acme//orders/shard-7",
+		"missing": "xy",
+	}
+	for key, value := range expected {
+		attr, exists := logRecord.Attributes().Get(key)
+		require.True(t, exists, "Expected %s attribute to exist", key)
+		assert.Equal(t, value, attr.Str(), "Routing attribute %s mismatch", key)
+	}
+}
+
+// TestSpanContextOnlyIfRemote tests that span context is only injected for spans with a remote parent
+func TestSpanContextOnlyIfRemote(t *testing.T) {
+	parentSpanID := pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+
+	tests := []struct {
+		name            string
+		kind            ptrace.SpanKind
+		parentSpanID    pcommon.SpanID
+		flags           uint32
+		expectedContext bool
+	}{
+		{"Flags mark parent as remote", ptrace.SpanKindInternal, parentSpanID, 0x100 | 0x200, true},
+		{"Flags mark parent as local", ptrace.SpanKindServer, parentSpanID, 0x100, false},
+		{"Server span with parent and no flags", ptrace.SpanKindServer, parentSpanID, 0, true},
+		{"Consumer span with parent and no flags", ptrace.SpanKindConsumer, parentSpanID, 0, true},
+		{"Internal span with parent and no flags", ptrace.SpanKindInternal, parentSpanID, 0, false},
+		{"Root server span and no flags", ptrace.SpanKindServer, pcommon.NewSpanIDEmpty(), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.SetKind(tt.kind)
+			span.SetParentSpanID(tt.parentSpanID)
+			span.SetFlags(tt.flags)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanContext:      true,
+				SpanContextOnlyIfRemote: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			if tt.expectedContext {
+				assert.Equal(t, span.TraceID(), logRecord.TraceID(), "Expected trace ID to be set")
+				assert.Equal(t, span.SpanID(), logRecord.SpanID(), "Expected span ID to be set")
+			} else {
+				assert.True(t, logRecord.TraceID().IsEmpty(), "Expected trace ID to be empty")
+				assert.True(t, logRecord.SpanID().IsEmpty(), "Expected span ID to be empty")
+			}
+		})
+	}
+}
+
+// TestDoubleAttributePrecision tests that copied double attributes are rounded to the configured precision
+func TestDoubleAttributePrecision(t *testing.T) {
+	two := 2
+	zero := 0
+	negative := -1
+	tests := []struct {
+		name              string
+		precision         *int
+		expectedDuration  float64
+		expectedSpanRatio float64
+		expectedNested    float64
+	}{
+		{"Round to 2 places", &two, 123.46, 0.33, 2.72},
+		{"Round to 0 places", &zero, 123, 0, 3},
+		{"Not configured disables rounding", nil, 123.456789, 1.0 / 3, 2.718281828},
+		{"Negative disables rounding", &negative, 123.456789, 1.0 / 3, 2.718281828},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.Attributes().PutDouble("sample.ratio", 1.0/3)
+			event := span.Events().At(0)
+			event.Attributes().PutDouble("duration_ms", 123.456789)
+			event.Attributes().PutEmptyMap("nested").PutDouble("e", 2.718281828)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:        []string{"event.attributes", "span.attributes"},
+				DoubleAttributePrecision: tt.precision,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			attrs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+			duration, _ := attrs.Get("duration_ms")
+			assert.Equal(t, tt.expectedDuration, duration.Double(), "Event attribute precision mismatch")
+			ratio, _ := attrs.Get("sample.ratio")
+			assert.Equal(t, tt.expectedSpanRatio, ratio.Double(), "Span attribute precision mismatch")
+			nested, _ := attrs.Get("nested")
+			e, _ := nested.Map().Get("e")
+			assert.Equal(t, tt.expectedNested, e.Double(), "Nested attribute precision mismatch")
+			severityNumber, _ := attrs.Get("event.severity_number")
+			assert.Equal(t, int64(9), severityNumber.Int(), "Int attributes should not be affected")
+		})
+	}
+}
+
+// TestBodyModeAttributesMap tests that the attributes_map body mode moves event attributes into the body
+func TestBodyModeAttributesMap(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().PutStr("http.method", "GET")
+	event := span.Events().At(0)
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes", "span.attributes"},
+		BodyMode:          "attributes_map",
+		AttributeMappings: config.AttributeMappings{
+			Body: "event.body",
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, pcommon.ValueTypeMap, logRecord.Body().Type(), "Expected a map body")
+	assert.Equal(t, event.Attributes().AsRaw(), logRecord.Body().Map().AsRaw(), "Body should equal the event attributes")
+
+	// Event attributes are not duplicated into the log attributes, but span attributes still are
+	_, exists := logRecord.Attributes().Get("event.body")
+	assert.False(t, exists, "Event attributes should not be copied to log attributes")
+	method, exists := logRecord.Attributes().Get("http.method")
+	require.True(t, exists, "Span attributes should still be copied")
+	assert.Equal(t, "GET", method.Str())
+}
+
+// TestEmitAbsenceLogForSpanAttribute tests that spans of interest without matching events produce an absence log
+func TestEmitAbsenceLogForSpanAttribute(t *testing.T) {
+	traces := ptrace.NewTraces()
+	scopeSpans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	scopeSpans.Scope().SetName("test-scope")
+
+	// Span of interest without an exception event
+	quietSpan := scopeSpans.Spans().AppendEmpty()
+	quietSpan.SetName("quiet-checkout")
+	quietSpan.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	quietSpan.SetSpanID(pcommon.SpanID([8]byte{1, 1, 1, 1, 1, 1, 1, 1}))
+	quietSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(100, 0)))
+	quietSpan.Attributes().PutStr("app.flow", "checkout")
+	quietSpan.Events().AppendEmpty().SetName("custom")
+
+	// Span of interest with an exception event
+	failingSpan := scopeSpans.Spans().AppendEmpty()
+	failingSpan.SetName("failing-checkout")
+	failingSpan.Attributes().PutStr("app.flow", "checkout")
+	failingSpan.Events().AppendEmpty().SetName("exception")
+
+	// Span not of interest without events
+	otherSpan := scopeSpans.Spans().AppendEmpty()
+	otherSpan.SetName("browse")
+	otherSpan.Attributes().PutStr("app.flow", "browse")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEventNames:              []string{"exception"},
+		IncludeSpanContext:             true,
+		EmitAbsenceLogForSpanAttribute: map[string]string{"app.flow": "checkout"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	require.Equal(t, []string{"no matching events", "exception"}, collectLogBodies(logsSink))
+
+	absenceLog := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, plog.SeverityNumberInfo, absenceLog.SeverityNumber())
+	assert.Equal(t, "info", absenceLog.SeverityText())
+	assert.Equal(t, quietSpan.EndTimestamp(), absenceLog.Timestamp(), "Absence log should use the span end time")
+	assert.Equal(t, quietSpan.SpanID(), absenceLog.SpanID(), "Absence log should carry the span context")
+	spanName, _ := absenceLog.Attributes().Get("span.name")
+	assert.Equal(t, "quiet-checkout", spanName.Str())
+}
+
+// TestAttributeRenameRules tests regex-based renaming of copied attribute keys
+func TestAttributeRenameRules(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().PutStr("db.system", "dynamodb")
+	event := span.Events().At(0)
+	event.Attributes().PutStr("db.statement", "PutItem")
+	event.Attributes().PutStr("http.request.method", "POST")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes", "span.attributes"},
+		AttributeRenameRules: []config.AttributeRenameRule{
+			{Pattern: `^db\.(.*)$`, Replacement: "database.$1"},
+			// Never applied to db.* keys since the first matching rule wins
+			{Pattern: `^db\.statement$`, Replacement: "query"},
+			{Pattern: `^event\.`, Replacement: "evt."},
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	attrs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	expected := map[string]string{
+		"database.statement":  "PutItem",
+		"database.system":     "dynamodb",
+		"evt.body":            "Successfully wrote TODO 5770916c-3838-4443-b4a8-f2b90366e235 to DynamoDB",
+		"http.request.method": "POST",
+	}
+	for key, value := range expected {
+		attr, exists := attrs.Get(key)
+		require.True(t, exists, "Expected %s attribute to exist", key)
+		assert.Equal(t, value, attr.Str())
+	}
+	for _, key := range []string{"db.statement", "db.system", "query", "event.body"} {
+		_, exists := attrs.Get(key)
+		assert.False(t, exists, "Original key %s should have been renamed", key)
+	}
+}
+
+// TestAttributeRenames tests that copied attributes are moved to their mapped keys, overwriting collisions
+func TestAttributeRenames(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Attributes().PutStr("http.method", "POST")
+	event := span.Events().At(0)
+	event.Attributes().PutStr("log.message", "stale")
+	event.Attributes().PutStr("a", "first")
+	event.Attributes().PutStr("b", "second")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes", "span.attributes"},
+		AttributeRenames: map[string]string{
+			"event.body":  "log.message",
+			"http.method": "http.request.method",
+			// Renames don't chain: "a" ends up under "b" and "b" under "c"
+			"a": "b",
+			"b": "c",
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	attrs := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	expected := map[string]string{
+		"log.message":         "Successfully wrote TODO 5770916c-3838-4443-b4a8-f2b90366e235 to DynamoDB",
+		"http.request.method": "POST",
+		"b":                   "first",
+		"c":                   "second",
+	}
+	for key, value := range expected {
+		attr, exists := attrs.Get(key)
+		require.True(t, exists, "Expected %s attribute to exist", key)
+		assert.Equal(t, value, attr.Str())
+	}
+	for _, key := range []string{"event.body", "http.method", "a"} {
+		_, exists := attrs.Get(key)
+		assert.False(t, exists, "Original key %s should have been renamed", key)
+	}
+}
+
+// TestSeverityForUnmatchedEvents tests that events matching no severity rule get the configured severity
+func TestSeverityForUnmatchedEvents(t *testing.T) {
+	traces := createTestTracesWithEventNames("exception", "cache.hit", "db.retry")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SeverityByEventName: map[string]string{
+			"exception": "error",
+			"retry":     "warn",
+		},
+		SeverityForUnmatchedEvents: "debug",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	expected := map[string]plog.SeverityNumber{
+		"exception": plog.SeverityNumberError,
+		"cache.hit": plog.SeverityNumberDebug,
+		"db.retry":  plog.SeverityNumberWarn,
+	}
+	logRecords := collectLogRecords(logsSink)
+	require.Equal(t, len(expected), len(logRecords))
+	for _, logRecord := range logRecords {
+		assert.Equal(t, expected[logRecord.Body().Str()], logRecord.SeverityNumber(), "Severity mismatch for %s", logRecord.Body().Str())
+		assert.Equal(t, severityNumberToText(expected[logRecord.Body().Str()]), logRecord.SeverityText())
+	}
+}
+
+// TestIncludeSpanNameHash tests that a stable hash of the span name is added when configured
+func TestIncludeSpanNameHash(t *testing.T) {
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeSpanNameHash: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	// Consume twice to ensure the hash is stable across batches
+	for i := 0; i < 2; i++ {
+		err = connector.ConsumeTraces(context.Background(), createTestTracesWithStructuredEvent())
+		assert.NoError(t, err)
+	}
+
+	logRecords := collectLogRecords(logsSink)
+	require.Equal(t, 2, len(logRecords))
+	for _, logRecord := range logRecords {
+		hash, exists := logRecord.Attributes().Get("span.name_hash")
+		require.True(t, exists, "Expected span.name_hash attribute to exist")
+		// FNV-1a 32-bit hash of "test-span"
+		assert.Equal(t, "b71fb008", hash.Str())
+	}
+}
+
+// TestTimestampSources tests that the log timestamp comes from the first available configured source
+func TestTimestampSources(t *testing.T) {
+	eventTime := time.Unix(1000, 0)
+	spanStart := time.Unix(900, 0)
+	attrTime := time.Unix(950, 500)
+	now := time.Unix(2000, 0)
+
+	tests := []struct {
+		name              string
+		sources           []string
+		eventTimestamp    time.Time
+		spanEnd           time.Time
+		attributes        map[string]any
+		expectedTimestamp time.Time
+	}{
+		{
+			name:              "No sources defaults to event time",
+			eventTimestamp:    eventTime,
+			expectedTimestamp: eventTime,
+		},
+		{
+			name:              "Span start before event time",
+			sources:           []string{"span_start", "event_time"},
+			eventTimestamp:    eventTime,
+			expectedTimestamp: spanStart,
+		},
+		{
+			name:              "Missing span end falls through to event time",
+			sources:           []string{"span_end", "event_time"},
+			eventTimestamp:    eventTime,
+			expectedTimestamp: eventTime,
+		},
+		{
+			name:              "Int attribute as Unix nanoseconds",
+			sources:           []string{"attribute:log.time", "event_time"},
+			eventTimestamp:    eventTime,
+			attributes:        map[string]any{"log.time": attrTime.UnixNano()},
+			expectedTimestamp: attrTime,
+		},
+		{
+			name:              "RFC 3339 string attribute",
+			sources:           []string{"attribute:log.time", "event_time"},
+			eventTimestamp:    eventTime,
+			attributes:        map[string]any{"log.time": attrTime.UTC().Format(time.RFC3339Nano)},
+			expectedTimestamp: attrTime,
+		},
+		{
+			name:              "Missing attribute and event time fall through to now",
+			sources:           []string{"attribute:log.time", "event_time", "now"},
+			expectedTimestamp: now,
+		},
+		{
+			name:              "Unparseable attribute falls through",
+			sources:           []string{"attribute:log.time", "span_start"},
+			attributes:        map[string]any{"log.time": "yesterday"},
+			expectedTimestamp: spanStart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("db.query")
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(spanStart))
+			if !tt.spanEnd.IsZero() {
+				span.SetEndTimestamp(pcommon.NewTimestampFromTime(tt.spanEnd))
+			}
+			event := span.Events().At(0)
+			event.SetTimestamp(0)
+			if !tt.eventTimestamp.IsZero() {
+				event.SetTimestamp(pcommon.NewTimestampFromTime(tt.eventTimestamp))
+			}
+			require.NoError(t, event.Attributes().FromRaw(tt.attributes))
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				TimestampSources: tt.sources,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+			connector.now = func() time.Time { return now }
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			assert.Equal(t, tt.expectedTimestamp.UnixNano(), logRecord.Timestamp().AsTime().UnixNano())
+			assert.Equal(t, now.UnixNano(), logRecord.ObservedTimestamp().AsTime().UnixNano(), "Observed timestamp should use the connector clock")
+		})
+	}
+}
+
+// TestObservedTimestamp tests that the observed timestamp follows the configured source
+func TestObservedTimestamp(t *testing.T) {
+	eventTime := time.Unix(1000, 0)
+	now := time.Unix(2000, 0)
+
+	tests := []struct {
+		name             string
+		mode             string
+		expectedObserved pcommon.Timestamp
+	}{
+		{"Default", "", pcommon.NewTimestampFromTime(now)},
+		{"Now", "now", pcommon.NewTimestampFromTime(now)},
+		{"Event", "event", pcommon.NewTimestampFromTime(eventTime)},
+		{"None", "none", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("db.query")
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).SetTimestamp(pcommon.NewTimestampFromTime(eventTime))
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				ObservedTimestamp: tt.mode,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+			connector.now = func() time.Time { return now }
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			assert.Equal(t, tt.expectedObserved, logRecord.ObservedTimestamp())
+			assert.Equal(t, pcommon.NewTimestampFromTime(eventTime), logRecord.Timestamp(), "Timestamp should not be affected")
+		})
+	}
+}
+
+// TestEventTimeWindow tests that only events whose timestamp falls within the configured window are converted
+func TestEventTimeWindow(t *testing.T) {
+	tests := []struct {
+		name           string
+		minEventTime   string
+		maxEventTime   string
+		expectedBodies []string
+	}{
+		{
+			name:           "Closed window includes its bounds",
+			minEventTime:   "2024-05-01T10:00:00Z",
+			maxEventTime:   "2024-05-01T11:00:00Z",
+			expectedBodies: []string{"at-min", "inside", "at-max"},
+		},
+		{
+			name:           "Open-ended window",
+			minEventTime:   "2024-05-01T10:30:00+00:00",
+			expectedBodies: []string{"inside", "at-max", "after"},
+		},
+		{
+			name:           "No window",
+			expectedBodies: []string{"before", "at-min", "inside", "at-max", "after"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("before", "at-min", "inside", "at-max", "after")
+			events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+			base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			for i, offset := range []time.Duration{-time.Second, 0, 45 * time.Minute, time.Hour, time.Hour + time.Second} {
+				events.At(i).SetTimestamp(pcommon.NewTimestampFromTime(base.Add(offset)))
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				MinEventTime: tt.minEventTime,
+				MaxEventTime: tt.maxEventTime,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestFilterConditions tests that only events passing all OTTL filter conditions are converted, alongside the name filters
+func TestFilterConditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		excludeNames   []string
+		expectedBodies []string
+	}{
+		{
+			name:           "Conditions only",
+			expectedBodies: []string{"db.failure", "db.retry"},
+		},
+		{
+			name:           "Conditions and exclude event names",
+			excludeNames:   []string{"db.retry"},
+			expectedBodies: []string{"db.failure"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("heartbeat", "db.failure", "db.retry", "db.ok")
+			events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+			for i := 0; i < 3; i++ {
+				events.At(i).Attributes().PutBool("error", true)
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				FilterConditions: []string{
+					`attributes["error"] == true and name != "heartbeat"`,
+					`span.name == "test-span"`,
+				},
+				ExcludeEventNames: tt.excludeNames,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+
+	t.Run("Invalid condition", func(t *testing.T) {
+		cfg := config.Config{
+			FilterConditions: []string{`attributes["error"] ==`},
+		}
+		_, err := newConnector(createTestConnectorSettings(t), cfg, new(consumertest.LogsSink))
+		assert.ErrorContains(t, err, "invalid filter conditions")
+	})
+}
+
+// TestTransformStatements tests that OTTL statements are run against each produced log record
+func TestTransformStatements(t *testing.T) {
+	traces := createTestTracesWithEventNames("payment.retry", "payment.done")
+	events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+	events.At(0).Attributes().PutInt("retry", 5)
+	events.At(0).Attributes().PutStr("internal.token", "secret-1")
+	events.At(1).Attributes().PutInt("retry", 1)
+	events.At(1).Attributes().PutStr("internal.token", "secret-2")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes"},
+		TransformStatements: []string{
+			// Fails at runtime since the attribute is missing, without affecting the other statements
+			`set(attributes["token.prefix"], Substring(attributes["missing"], 0, 4))`,
+			`set(severity_text, "WARN") where attributes["retry"] > 3`,
+			`delete_key(attributes, "internal.token")`,
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2, "Records should be kept when a statement fails")
+	assert.Equal(t, "WARN", logRecords[0].SeverityText())
+	assert.Equal(t, "info", logRecords[1].SeverityText())
+	for _, logRecord := range logRecords {
+		_, exists := logRecord.Attributes().Get("internal.token")
+		assert.False(t, exists, "Expected internal.token to be deleted")
+		_, exists = logRecord.Attributes().Get("token.prefix")
+		assert.False(t, exists, "Failed statement should not set an attribute")
+	}
+
+	t.Run("Invalid statement", func(t *testing.T) {
+		cfg := config.Config{
+			TransformStatements: []string{`set(severity_text,`},
+		}
+		_, err := newConnector(createTestConnectorSettings(t), cfg, new(consumertest.LogsSink))
+		assert.ErrorContains(t, err, "invalid transform statements")
+	})
+}
+
+// TestTimestampFallback tests that unset event timestamps fall back to the span start time, then to the current time
+func TestTimestampFallback(t *testing.T) {
+	eventTime := time.Unix(1000, 0)
+	spanStart := time.Unix(900, 0)
+	now := time.Unix(2000, 0)
+
+	tests := []struct {
+		name              string
+		fallback          bool
+		eventTimestamp    time.Time
+		spanStart         time.Time
+		expectedTimestamp time.Time
+	}{
+		{
+			name:              "Event time is kept",
+			fallback:          true,
+			eventTimestamp:    eventTime,
+			spanStart:         spanStart,
+			expectedTimestamp: eventTime,
+		},
+		{
+			name:              "Unset event time falls back to span start",
+			fallback:          true,
+			spanStart:         spanStart,
+			expectedTimestamp: spanStart,
+		},
+		{
+			name:              "Unset event time and span start fall back to now",
+			fallback:          true,
+			expectedTimestamp: now,
+		},
+		{
+			name:      "Disabled keeps the unset event time",
+			fallback:  false,
+			spanStart: spanStart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("db.query")
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			if !tt.spanStart.IsZero() {
+				span.SetStartTimestamp(pcommon.NewTimestampFromTime(tt.spanStart))
+			}
+			event := span.Events().At(0)
+			event.SetTimestamp(0)
+			if !tt.eventTimestamp.IsZero() {
+				event.SetTimestamp(pcommon.NewTimestampFromTime(tt.eventTimestamp))
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				TimestampFallback: tt.fallback,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+			connector.now = func() time.Time { return now }
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			if tt.expectedTimestamp.IsZero() {
+				assert.Equal(t, pcommon.Timestamp(0), logRecord.Timestamp())
+			} else {
+				assert.Equal(t, tt.expectedTimestamp.UnixNano(), logRecord.Timestamp().AsTime().UnixNano())
+			}
+		})
+	}
+}
+
+// TestAccumulateDroppedCounts tests that the event's dropped attributes count is carried to the log record
+func TestAccumulateDroppedCounts(t *testing.T) {
+	tests := []struct {
+		name          string
+		accumulate    bool
+		expectedCount uint32
+	}{
+		{"Enabled", true, 3},
+		{"Disabled", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.SetDroppedAttributesCount(3)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:       []string{"event.attributes"},
+				AccumulateDroppedCounts: tt.accumulate,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			assert.Equal(t, tt.expectedCount, logRecord.DroppedAttributesCount())
+		})
+	}
+}
+
+// TestNumericAttributeFilters tests filtering events by numeric attribute ranges
+func TestNumericAttributeFilters(t *testing.T) {
+	traces := createTestTracesWithEventNames("fast", "slow", "very.slow", "unknown", "text", "retried")
+	events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+	events.At(0).Attributes().PutInt("duration_ms", 50)
+	events.At(1).Attributes().PutInt("duration_ms", 100)
+	events.At(2).Attributes().PutDouble("duration_ms", 5000.5)
+	// events.At(3) has no duration
+	events.At(4).Attributes().PutStr("duration_ms", "300")
+	events.At(5).Attributes().PutInt("duration_ms", 10)
+	events.At(5).Attributes().PutInt("retry.count", 3)
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		NumericAttributeFilters: []config.NumericAttributeFilter{
+			{Key: "duration_ms", Min: 100, Max: 5000},
+			{Key: "retry.count", Min: 1, Max: 5},
+		},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"slow", "retried"}, collectLogBodies(logsSink))
+}
+
+// TestBytesAttributeEncoding tests the handling of bytes attribute values during copy
+func TestBytesAttributeEncoding(t *testing.T) {
+	payload := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	tests := []struct {
+		name     string
+		encoding string
+		expected any
+	}{
+		{"Raw by default", "", payload},
+		{"Raw", "raw", payload},
+		{"Base64", "base64", "3q2+7w=="},
+		{"Hex", "hex", "deadbeef"},
+		{"Drop", "drop", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutEmptyBytes("payload").FromRaw(payload)
+			event.Attributes().PutEmptySlice("chunks").AppendEmpty().SetEmptyBytes().FromRaw(payload)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:      []string{"event.attributes"},
+				BytesAttributeEncoding: tt.encoding,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			attrs := collectLogRecords(logsSink)[0].Attributes()
+			value, exists := attrs.Get("payload")
+			chunks, _ := attrs.Get("chunks")
+			if tt.expected == nil {
+				assert.False(t, exists, "Bytes attribute should be dropped")
+				assert.Equal(t, 0, chunks.Slice().Len(), "Nested bytes values should be dropped")
+				return
+			}
+			require.True(t, exists, "Expected payload attribute to exist")
+			assert.Equal(t, tt.expected, value.AsRaw())
+			assert.Equal(t, tt.expected, chunks.Slice().At(0).AsRaw(), "Nested bytes values should be encoded")
+
+			// Other attributes are unaffected
+			body, _ := attrs.Get("event.body")
+			assert.Equal(t, pcommon.ValueTypeStr, body.Type())
+		})
+	}
+}
+
+// TestDebugTraceSeverityResolution tests that severity resolution is recorded on the extraction span
+func TestDebugTraceSeverityResolution(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		expectedEvents []map[attribute.Key]attribute.Value
+	}{
+		{
+			name:    "Enabled",
+			enabled: true,
+			expectedEvents: []map[attribute.Key]attribute.Value{
+				{
+					"event.name":      attribute.StringValue("exception"),
+					"severity.source": attribute.StringValue("severity_by_event_name"),
+					"severity.number": attribute.IntValue(int(plog.SeverityNumberError)),
+					"severity.text":   attribute.StringValue("error"),
+				},
+				{
+					"event.name":      attribute.StringValue("custom"),
+					"severity.source": attribute.StringValue("default"),
+					"severity.number": attribute.IntValue(int(plog.SeverityNumberInfo)),
+					"severity.text":   attribute.StringValue("info"),
+				},
+			},
+		},
+		{
+			name:    "Disabled",
+			enabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			settings := createTestConnectorSettings(t)
+			settings.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByEventName:          map[string]string{"exception": "error"},
+				DebugTraceSeverityResolution: tt.enabled,
+			}
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), createTestTraces())
+			assert.NoError(t, err)
+
+			var resolutions []map[attribute.Key]attribute.Value
+			for _, span := range recorder.Ended() {
+				if span.Name() != "connector/spaneventtolog/ExtractLogs" {
+					continue
+				}
+				for _, event := range span.Events() {
+					if event.Name != "severity_resolution" {
+						continue
+					}
+					attrs := map[attribute.Key]attribute.Value{}
+					for _, kv := range event.Attributes {
+						attrs[kv.Key] = kv.Value
+					}
+					resolutions = append(resolutions, attrs)
+				}
+			}
+			assert.Equal(t, tt.expectedEvents, resolutions)
+		})
+	}
+}
+
+// TestDetailedSpanAttributes tests that the extraction span breaks its counts down by severity and drop reason
+func TestDetailedSpanAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		expected map[attribute.Key]attribute.Value
+	}{
+		{
+			name:    "Enabled",
+			enabled: true,
+			expected: map[attribute.Key]attribute.Value{
+				"logs_by_severity.error":    attribute.IntValue(1),
+				"logs_by_severity.info":     attribute.IntValue(1),
+				"events_dropped.event_name": attribute.IntValue(1),
+			},
+		},
+		{
+			name:     "Disabled",
+			enabled:  false,
+			expected: map[attribute.Key]attribute.Value{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			settings := createTestConnectorSettings(t)
+			settings.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeEventNames:      []string{"exception", "log"},
+				SeverityByEventName:    map[string]string{"exception": "error"},
+				AggregateExceptions:    true,
+				DetailedSpanAttributes: tt.enabled,
+			}
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), createTestTracesWithEventNames("exception", "exception", "log", "debug"))
+			assert.NoError(t, err)
+
+			detailed := map[attribute.Key]attribute.Value{}
+			for _, span := range recorder.Ended() {
+				if span.Name() != "connector/spaneventtolog/ExtractLogs" {
+					continue
+				}
+				for _, kv := range span.Attributes() {
+					key := string(kv.Key)
+					// The aggregated exception is counted as processed only
+					if key == "events_processed" {
+						assert.Equal(t, int64(3), kv.Value.AsInt64())
+					}
+					if strings.HasPrefix(key, "logs_by_severity.") || strings.HasPrefix(key, "events_dropped.") {
+						detailed[kv.Key] = kv.Value
+					}
+				}
+			}
+			assert.Equal(t, tt.expected, detailed)
+		})
+	}
+}
+
+// TestErrorTracesOnly tests that only events from traces containing an error span are converted
+func TestErrorTracesOnly(t *testing.T) {
+	errorTraceID := pcommon.TraceID([16]byte{1})
+	okTraceID := pcommon.TraceID([16]byte{2})
+
+	traces := ptrace.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan := func(traceID pcommon.TraceID, status ptrace.StatusCode, eventName string) {
+		span := spans.AppendEmpty()
+		span.SetTraceID(traceID)
+		span.Status().SetCode(status)
+		span.Events().AppendEmpty().SetName(eventName)
+	}
+	addSpan(errorTraceID, ptrace.StatusCodeOk, "error-trace.ok-span")
+	addSpan(errorTraceID, ptrace.StatusCodeError, "error-trace.error-span")
+	addSpan(okTraceID, ptrace.StatusCodeOk, "ok-trace.ok-span")
+	addSpan(okTraceID, ptrace.StatusCodeUnset, "ok-trace.unset-span")
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		expectedBodies []string
+	}{
+		{"Enabled", true, []string{"error-trace.ok-span", "error-trace.error-span"}},
+		{"Disabled", false, []string{"error-trace.ok-span", "error-trace.error-span", "ok-trace.ok-span", "ok-trace.unset-span"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				ErrorTracesOnly: tt.enabled,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestCorrelationKey tests building a composite correlation key from multiple attribute sources
+func TestCorrelationKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestID   string
+		expectedKey string
+	}{
+		{"All parts present", "req-42", "test-service:GET:req-42"},
+		{"Missing part renders empty", "", "test-service:GET:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.Attributes().PutStr("http.method", "GET")
+			if tt.requestID != "" {
+				span.Events().At(0).Attributes().PutStr("request.id", tt.requestID)
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				CorrelationKey: config.CorrelationKey{
+					Attribute: "correlation.key",
+					Template:  "{resource:service.name}:{span:http.method}:{event:request.id}",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			key, exists := collectLogRecords(logsSink)[0].Attributes().Get("correlation.key")
+			require.True(t, exists, "Expected correlation.key attribute to exist")
+			assert.Equal(t, tt.expectedKey, key.Str())
+		})
+	}
+}
+
+// TestSecondaryBodyAttribute tests that the secondary body attribute is used when the primary one is missing
+func TestSecondaryBodyAttribute(t *testing.T) {
+	tests := []struct {
+		name          string
+		primaryBody   string
+		secondaryBody string
+		expectedBody  string
+	}{
+		{"Primary present", "primary body", "secondary body", "primary body"},
+		{"Primary missing, secondary present", "", "secondary body", "secondary body"},
+		{"Both missing", "", "", "test-event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			if tt.primaryBody != "" {
+				event.Attributes().PutStr("event.body", tt.primaryBody)
+			}
+			if tt.secondaryBody != "" {
+				event.Attributes().PutStr("message", tt.secondaryBody)
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SecondaryBodyAttribute: "message",
+				AttributeMappings: config.AttributeMappings{
+					Body: "event.body",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []string{tt.expectedBody}, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestDropEmptyAttributes tests dropping empty string and empty collection attributes
+func TestDropEmptyAttributes(t *testing.T) {
+	tests := []struct {
+		name            string
+		dropCollections bool
+		expectedPresent []string
+		expectedAbsent  []string
+	}{
+		{
+			name:            "Drop empty strings only",
+			expectedPresent: []string{"non_empty", "empty_map", "empty_slice"},
+			expectedAbsent:  []string{"empty_str"},
+		},
+		{
+			name:            "Drop empty strings and collections",
+			dropCollections: true,
+			expectedPresent: []string{"non_empty"},
+			expectedAbsent:  []string{"empty_str", "empty_map", "empty_slice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+			attrs.PutStr("non_empty", "value")
+			attrs.PutStr("empty_str", "")
+			attrs.PutEmptyMap("empty_map")
+			attrs.PutEmptySlice("empty_slice")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:             []string{"event.attributes"},
+				DropEmptyStringAttributes:     true,
+				DropEmptyCollectionAttributes: tt.dropCollections,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logAttrs := collectLogRecords(logsSink)[0].Attributes()
+			for _, key := range tt.expectedPresent {
+				_, exists := logAttrs.Get(key)
+				assert.True(t, exists, "Expected %s attribute to be kept", key)
+			}
+			for _, key := range tt.expectedAbsent {
+				_, exists := logAttrs.Get(key)
+				assert.False(t, exists, "Expected %s attribute to be dropped", key)
+			}
+		})
+	}
+}
+
+// TestFlattenAttributes tests that nested map and slice attributes are flattened into dotted and indexed keys
+func TestFlattenAttributes(t *testing.T) {
+	traces := createTestTracesWithEventNames("request")
+	attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+	httpAttrs := attrs.PutEmptyMap("http")
+	httpAttrs.PutInt("status_code", 503)
+	retry := httpAttrs.PutEmptyMap("retry")
+	retry.PutEmptyMap("policy").PutStr("name", "exponential")
+	retry.PutInt("attempts", 3)
+	tags := attrs.PutEmptySlice("tags")
+	tags.AppendEmpty().SetStr("checkout")
+	tags.AppendEmpty().SetEmptyMap().PutStr("tier", "gold")
+	attrs.PutEmptyMap("empty")
+	attrs.PutStr("plain", "value")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes"},
+		FlattenAttributes: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecord := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, map[string]any{
+		"http.status_code":       int64(503),
+		"http.retry.policy.name": "exponential",
+		"http.retry.attempts":    int64(3),
+		"tags.0":                 "checkout",
+		"tags.1.tier":            "gold",
+		"empty":                  map[string]any{},
+		"plain":                  "value",
+	}, logRecord.Attributes().AsRaw())
+}
+
+// TestFlattenAttributesWithMaxKeyDepth tests that flattened keys are limited to the configured depth
+func TestFlattenAttributesWithMaxKeyDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		dropExceeding bool
+		expected      map[string]any
+	}{
+		{
+			name: "Truncate deep flattened keys",
+			expected: map[string]any{
+				"http.status_code": int64(503),
+				"http.retry":       "exponential",
+				"tags.0":           "checkout",
+			},
+		},
+		{
+			name:          "Drop deep flattened keys",
+			dropExceeding: true,
+			expected: map[string]any{
+				"http.status_code": int64(503),
+				"tags.0":           "checkout",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("request")
+			attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+			httpAttrs := attrs.PutEmptyMap("http")
+			httpAttrs.PutInt("status_code", 503)
+			httpAttrs.PutEmptyMap("retry").PutEmptyMap("policy").PutStr("name", "exponential")
+			attrs.PutEmptySlice("tags").AppendEmpty().SetStr("checkout")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:      []string{"event.attributes"},
+				FlattenAttributes:      true,
+				MaxKeyDepth:            2,
+				DropKeysExceedingDepth: tt.dropExceeding,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expected, logRecords[0].Attributes().AsRaw())
+		})
+	}
+}
+
+// TestStringifyAllAttributes tests that copied attribute values are converted to strings
+func TestStringifyAllAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		flatten  bool
+		expected map[string]any
+	}{
+		{
+			name: "Stringified",
+			expected: map[string]any{
+				"retry":    "3",
+				"cached":   "true",
+				"ratio":    "0.5",
+				"user.id":  "u-1",
+				"response": `{"status":503}`,
+			},
+		},
+		{
+			name:    "Stringified and flattened",
+			flatten: true,
+			expected: map[string]any{
+				"retry":           "3",
+				"cached":          "true",
+				"ratio":           "0.5",
+				"user.id":         "u-1",
+				"response.status": "503",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("request")
+			attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+			attrs.PutInt("retry", 3)
+			attrs.PutBool("cached", true)
+			attrs.PutDouble("ratio", 0.5)
+			attrs.PutStr("user.id", "u-1")
+			attrs.PutEmptyMap("response").PutInt("status", 503)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:      []string{"event.attributes"},
+				StringifyAllAttributes: true,
+				FlattenAttributes:      tt.flatten,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected, collectLogRecords(logsSink)[0].Attributes().AsRaw())
+		})
+	}
+}
+
+// TestMaxKeyDepth tests truncating or dropping attribute keys deeper than the configured depth
+func TestMaxKeyDepth(t *testing.T) {
+	tests := []struct {
+		name           string
+		dropExceeding  bool
+		expectedKeys   []string
+		unexpectedKeys []string
+	}{
+		{
+			name:           "Truncate deep keys",
+			expectedKeys:   []string{"a.b.c", "x.y", "span.http.request"},
+			unexpectedKeys: []string{"a.b.c.d.e", "span.http.request.method"},
+		},
+		{
+			name:           "Drop deep keys",
+			dropExceeding:  true,
+			expectedKeys:   []string{"x.y"},
+			unexpectedKeys: []string{"a.b.c", "a.b.c.d.e", "span.http.request", "span.http.request.method"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+			attrs.PutStr("a.b.c.d.e", "deep")
+			attrs.PutStr("x.y", "shallow")
+			// The span attribute prefix counts toward the depth
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().PutStr("http.request.method", "GET")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:      []string{"event.attributes", "span.attributes"},
+				SpanAttributePrefix:    "span.",
+				MaxKeyDepth:            3,
+				DropKeysExceedingDepth: tt.dropExceeding,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logAttrs := collectLogRecords(logsSink)[0].Attributes()
+			for _, key := range tt.expectedKeys {
+				_, exists := logAttrs.Get(key)
+				assert.True(t, exists, "Expected %s attribute to exist", key)
+			}
+			for _, key := range tt.unexpectedKeys {
+				_, exists := logAttrs.Get(key)
+				assert.False(t, exists, "Expected %s attribute not to exist", key)
+			}
+		})
+	}
+}
+
+// TestErrorEscalationThreshold tests escalating error records to fatal on spans with too many error events
+func TestErrorEscalationThreshold(t *testing.T) {
+	tests := []struct {
+		name             string
+		eventNames       []string
+		expectedSeverity []plog.SeverityNumber
+	}{
+		{
+			name:             "At threshold",
+			eventNames:       []string{"exception", "exception", "checkpoint"},
+			expectedSeverity: []plog.SeverityNumber{plog.SeverityNumberError, plog.SeverityNumberError, plog.SeverityNumberInfo},
+		},
+		{
+			name:             "Above threshold",
+			eventNames:       []string{"exception", "exception", "exception", "checkpoint"},
+			expectedSeverity: []plog.SeverityNumber{plog.SeverityNumberFatal, plog.SeverityNumberFatal, plog.SeverityNumberFatal, plog.SeverityNumberInfo},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames(tt.eventNames...)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByEventName:      map[string]string{"exception": "error"},
+				ErrorEscalationThreshold: 2,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, len(tt.expectedSeverity))
+			for i, expected := range tt.expectedSeverity {
+				assert.Equal(t, expected, logRecords[i].SeverityNumber())
+				assert.Equal(t, severityNumberToText(expected), logRecords[i].SeverityText())
+			}
+		})
+	}
+}
+
+// TestErrorEscalationWithAggregateExceptions tests that exception events folded into one record count toward the threshold
+func TestErrorEscalationWithAggregateExceptions(t *testing.T) {
+	traces := createTestTracesWithEventNames("exception", "exception", "exception", "checkpoint")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SeverityByEventName:      map[string]string{"exception": "error"},
+		AggregateExceptions:      true,
+		ErrorEscalationThreshold: 2,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	assert.Equal(t, plog.SeverityNumberFatal, logRecords[0].SeverityNumber())
+	assert.Equal(t, plog.SeverityNumberInfo, logRecords[1].SeverityNumber())
+}
+
+// TestErrorEscalationWithDowngrade tests that severity downgrades apply after error escalation
+func TestErrorEscalationWithDowngrade(t *testing.T) {
+	traces := createTestTracesWithEventNames("exception", "exception", "crash")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SeverityByEventName:      map[string]string{"exception": "error", "crash": "fatal"},
+		DowngradeSeverities:      map[string]string{"fatal": "error"},
+		ErrorEscalationThreshold: 2,
+		AddLevel:                 true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	// The span is over the threshold, but escalated records are downgraded back to error
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	for _, logRecord := range logRecords {
+		assert.Equal(t, plog.SeverityNumberError, logRecord.SeverityNumber())
+		assert.Equal(t, "error", logRecord.SeverityText())
+		level, ok := logRecord.Attributes().Get("level")
+		require.True(t, ok)
+		assert.Equal(t, "error", level.Str())
+	}
+}
+
+// TestErrorEscalationOrder tests that escalation updates the level attribute and runs before transforms
+func TestErrorEscalationOrder(t *testing.T) {
+	traces := createTestTracesWithEventNames("exception", "exception", "retry")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SeverityByEventName:      map[string]string{"exception": "error", "retry": "error"},
+		ErrorEscalationThreshold: 2,
+		AddLevel:                 true,
+		TransformStatements:      []string{`set(severity_text, "retrying") where body == "retry"`},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	expectedTexts := []string{"fatal", "fatal", "retrying"}
+	for i, logRecord := range logRecords {
+		assert.Equal(t, plog.SeverityNumberFatal, logRecord.SeverityNumber())
+		assert.Equal(t, expectedTexts[i], logRecord.SeverityText())
+		level, ok := logRecord.Attributes().Get("level")
+		require.True(t, ok)
+		assert.Equal(t, "fatal", level.Str())
+	}
+}
+
+// TestAnnotateBatchSize tests that each log record carries the span count of its source batch
+func TestAnnotateBatchSize(t *testing.T) {
+	traces := createTestTracesWithEventNames("first-event")
+	scopeSpans := traces.ResourceSpans().At(0).ScopeSpans().At(0)
+	secondSpan := scopeSpans.Spans().AppendEmpty()
+	secondSpan.SetName("second-span")
+	secondSpan.Events().AppendEmpty().SetName("second-event")
+	scopeSpans.Spans().AppendEmpty().SetName("span-without-events")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AnnotateBatchSize: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	for _, logRecord := range logRecords {
+		batchSpanCount, exists := logRecord.Attributes().Get("spaneventtolog.batch_span_count")
+		require.True(t, exists, "Expected spaneventtolog.batch_span_count attribute to exist")
+		assert.Equal(t, int64(3), batchSpanCount.Int())
+	}
+}
+
+// TestSkipSpanContextAttribute tests that events can opt out of span context injection
+func TestSkipSpanContextAttribute(t *testing.T) {
+	tests := []struct {
+		name                string
+		setAttr             func(pcommon.Map)
+		expectedSpanContext bool
+	}{
+		{"Attribute absent", func(pcommon.Map) {}, true},
+		{"Bool true", func(m pcommon.Map) { m.PutBool("skip_trace_context", true) }, false},
+		{"Bool false", func(m pcommon.Map) { m.PutBool("skip_trace_context", false) }, true},
+		{"String true", func(m pcommon.Map) { m.PutStr("skip_trace_context", "true") }, false},
+		{"Non-boolean string", func(m pcommon.Map) { m.PutStr("skip_trace_context", "maybe") }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			tt.setAttr(traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes())
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanContext:       true,
+				SkipSpanContextAttribute: "skip_trace_context",
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			assert.Equal(t, tt.expectedSpanContext, !logRecord.TraceID().IsEmpty())
+			_, hasSpanName := logRecord.Attributes().Get("span.name")
+			assert.Equal(t, tt.expectedSpanContext, hasSpanName)
+		})
+	}
+}
+
+// spanKindSeverityResolver is a custom SeverityResolver raising events on server spans to warn.
+type spanKindSeverityResolver struct{}
+
+func (spanKindSeverityResolver) Resolve(event ptrace.SpanEvent, span ptrace.Span) (plog.SeverityNumber, string, bool) {
+	if span.Kind() == ptrace.SpanKindServer && event.Name() != "" {
+		return plog.SeverityNumberWarn, "warn", true
+	}
+	return plog.SeverityNumberUnspecified, "", false
+}
+
+// TestSeverityResolver tests that a custom severity resolver injected via the factory is consulted first
+func TestSeverityResolver(t *testing.T) {
+	tests := []struct {
+		name             string
+		spanKind         ptrace.SpanKind
+		expectedSeverity plog.SeverityNumber
+	}{
+		{"Resolver resolves", ptrace.SpanKindServer, plog.SeverityNumberWarn},
+		{"Resolver defers to configured sources", ptrace.SpanKindInternal, plog.SeverityNumberError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("exception")
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetKind(tt.spanKind)
+
+			logsSink := new(consumertest.LogsSink)
+			factory := NewFactory(WithSeverityResolver(spanKindSeverityResolver{}))
+			cfg := &config.Config{
+				SeverityByEventName: map[string]string{"exception": "error"},
+			}
+			connectorInstance, err := factory.CreateTracesToLogs(context.Background(), createTestConnectorSettings(t), cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connectorInstance.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedSeverity, logRecords[0].SeverityNumber())
+		})
+	}
+}
+
+// TestIncludeTraceFlagsInt tests that the span's trace flags are recorded as an integer attribute
+func TestIncludeTraceFlagsInt(t *testing.T) {
+	tests := []struct {
+		name               string
+		includeSpanContext bool
+		spanFlags          uint32
+		expectedFlags      int64
+		expectAttribute    bool
+	}{
+		{"Sampled", true, 0x01, 1, true},
+		{"Not sampled", true, 0x00, 0, true},
+		{"Remote bits are excluded", true, 0x301, 1, true},
+		{"Span context excluded", false, 0x01, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetFlags(tt.spanFlags)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanContext:   tt.includeSpanContext,
+				IncludeTraceFlagsInt: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			flags, exists := collectLogRecords(logsSink)[0].Attributes().Get("trace.flags")
+			require.Equal(t, tt.expectAttribute, exists)
+			if exists {
+				assert.Equal(t, tt.expectedFlags, flags.Int())
+			}
+		})
+	}
+}
+
+// TestSequenceAttribute tests that emitted records carry increasing sequence numbers in processing order
+func TestSequenceAttribute(t *testing.T) {
+	traces := createTestTracesWithEventNames("first", "second")
+	secondSpan := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().AppendEmpty()
+	secondSpan.Events().AppendEmpty().SetName("third")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SequenceAttribute: "sequence",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	for i, logRecord := range logRecords {
+		sequence, exists := logRecord.Attributes().Get("sequence")
+		require.True(t, exists, "Expected sequence attribute to exist")
+		assert.Equal(t, int64(i), sequence.Int())
+		assert.Equal(t, []string{"first", "second", "third"}[i], logRecord.Body().Str())
+	}
+}
+
+// TestSpanParentID tests that the parent span ID is included with the span context for child spans only
+func TestSpanParentID(t *testing.T) {
+	tests := []struct {
+		name             string
+		parentSpanID     pcommon.SpanID
+		expectedParentID string
+	}{
+		{"Child span", pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}), "0807060504030201"},
+		{"Root span", pcommon.NewSpanIDEmpty(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(tt.parentSpanID)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanContext: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			parentID, exists := collectLogRecords(logsSink)[0].Attributes().Get("span.parent_id")
+			if tt.expectedParentID == "" {
+				assert.False(t, exists, "Root spans should not have a span.parent_id attribute")
+				return
+			}
+			require.True(t, exists, "Expected span.parent_id attribute to exist")
+			assert.Equal(t, tt.expectedParentID, parentID.Str())
+		})
+	}
+}
+
+// TestIncludeIsRoot tests that span.is_root reflects whether the span has a parent
+func TestIncludeIsRoot(t *testing.T) {
+	tests := []struct {
+		name         string
+		parentSpanID pcommon.SpanID
+		expectedRoot bool
+	}{
+		{"Root span", pcommon.NewSpanIDEmpty(), true},
+		{"Child span", pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithStructuredEvent()
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(tt.parentSpanID)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeIsRoot: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			isRoot, exists := collectLogRecords(logsSink)[0].Attributes().Get("span.is_root")
+			require.True(t, exists, "Expected span.is_root attribute to exist")
+			assert.Equal(t, tt.expectedRoot, isRoot.Bool())
+		})
+	}
+}
+
+// TestIncludeSpanDuration tests that the span duration is recorded in the configured unit for ended spans
+func TestIncludeSpanDuration(t *testing.T) {
+	start := time.Unix(1000, 0)
+	end := start.Add(1500*time.Millisecond + 250*time.Microsecond)
+
+	tests := []struct {
+		name          string
+		unit          string
+		end           time.Time
+		expectedKey   string
+		expectedValue any
+	}{
+		{"Default unit is milliseconds", "", end, "span.duration_ms", 1500.25},
+		{"Milliseconds", "ms", end, "span.duration_ms", 1500.25},
+		{"Nanoseconds", "ns", end, "span.duration_ns", int64(1500250000)},
+		{"Seconds", "s", end, "span.duration_s", 1.50025},
+		{"Span not ended", "ms", time.Time{}, "", nil},
+		{"Span ending before its start", "ms", start.Add(-time.Second), "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("db.query")
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			if !tt.end.IsZero() {
+				span.SetEndTimestamp(pcommon.NewTimestampFromTime(tt.end))
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanDuration: true,
+				SpanDurationUnit:    tt.unit,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			attrs := collectLogRecords(logsSink)[0].Attributes()
+			for _, key := range []string{"span.duration_ms", "span.duration_ns", "span.duration_s"} {
+				duration, exists := attrs.Get(key)
+				if key != tt.expectedKey {
+					assert.False(t, exists, "Unexpected %s attribute", key)
+					continue
+				}
+				require.True(t, exists, "Expected %s attribute to exist", key)
+				if expected, ok := tt.expectedValue.(float64); ok {
+					assert.InDelta(t, expected, duration.Double(), 1e-9)
+				} else {
+					assert.Equal(t, tt.expectedValue, duration.AsRaw())
+				}
+			}
+		})
+	}
+}
+
+// TestCollapseAttributesToJSON tests serializing several event attributes into one JSON string attribute
+func TestCollapseAttributesToJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		removeOriginals bool
+	}{
+		{"Keep originals", false},
+		{"Remove originals", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			attrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+			attrs.PutStr("user.id", "u-1")
+			attrs.PutInt("retry", 3)
+			attrs.PutBool("cached", true)
+			attrs.PutStr("unrelated", "kept")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom: []string{"event.attributes"},
+				CollapseAttributesToJSON: config.CollapseAttributesToJSON{
+					TargetKey:       "details",
+					SourceKeys:      []string{"user.id", "retry", "cached", "missing"},
+					RemoveOriginals: tt.removeOriginals,
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logAttrs := collectLogRecords(logsSink)[0].Attributes()
+			details, exists := logAttrs.Get("details")
+			require.True(t, exists, "Expected details attribute to exist")
+			assert.JSONEq(t, `{"user.id":"u-1","retry":3,"cached":true}`, details.Str())
+
+			_, hasUserID := logAttrs.Get("user.id")
+			assert.Equal(t, !tt.removeOriginals, hasUserID)
+			_, hasUnrelated := logAttrs.Get("unrelated")
+			assert.True(t, hasUnrelated, "Expected unrelated attribute to be kept")
+		})
+	}
+}
+
+// TestSeverityByNumericThreshold tests severity resolution from numeric attribute thresholds
+func TestSeverityByNumericThreshold(t *testing.T) {
+	tests := []struct {
+		name             string
+		setAttr          func(pcommon.Map)
+		expectedSeverity plog.SeverityNumber
+	}{
+		{"Below all thresholds", func(m pcommon.Map) { m.PutInt("response_time_ms", 200) }, plog.SeverityNumberInfo},
+		{"At the lower threshold", func(m pcommon.Map) { m.PutInt("response_time_ms", 1000) }, plog.SeverityNumberInfo},
+		{"Above the lower threshold", func(m pcommon.Map) { m.PutInt("response_time_ms", 1500) }, plog.SeverityNumberWarn},
+		{"Above the upper threshold", func(m pcommon.Map) { m.PutDouble("response_time_ms", 7500.5) }, plog.SeverityNumberError},
+		{"Non-numeric value", func(m pcommon.Map) { m.PutStr("response_time_ms", "9000") }, plog.SeverityNumberInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("http.response")
+			tt.setAttr(traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes())
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByNumericThreshold: config.SeverityByNumericThreshold{
+					Attribute: "response_time_ms",
+					// Rules are listed out of order on purpose; the highest threshold is evaluated first
+					Rules: []config.NumericThresholdRule{
+						{Threshold: 1000, Severity: "warn"},
+						{Threshold: 5000, Severity: "error"},
+					},
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedSeverity, logRecords[0].SeverityNumber())
+		})
+	}
+}
+
+// TestAnnotateResourceEventCount tests that each resource carries the number of records it holds
+func TestAnnotateResourceEventCount(t *testing.T) {
+	traces := createTestTracesWithEventNames("first", "second")
+	otherResource := traces.ResourceSpans().AppendEmpty()
+	otherResource.Resource().Attributes().PutStr("service.name", "other-service")
+	otherResource.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events().AppendEmpty().SetName("third")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AnnotateResourceEventCount: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	total := 0
+	for i := 0; i < allLogs[0].ResourceLogs().Len(); i++ {
+		resourceLogs := allLogs[0].ResourceLogs().At(i)
+		records := 0
+		for j := 0; j < resourceLogs.ScopeLogs().Len(); j++ {
+			records += resourceLogs.ScopeLogs().At(j).LogRecords().Len()
+		}
+		count, exists := resourceLogs.Resource().Attributes().Get("spaneventtolog.converted_events")
+		require.True(t, exists, "Expected spaneventtolog.converted_events attribute to exist")
+		assert.Equal(t, int64(records), count.Int())
+		total += int(count.Int())
+	}
+	assert.Equal(t, 3, total)
+}
+
+// TestSkipSpanContextForPatterns tests that span context is skipped for events matching a pattern
+func TestSkipSpanContextForPatterns(t *testing.T) {
+	traces := createTestTracesWithEventNames("order.8f3a2c.created", "order.created")
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeSpanContext:         true,
+		SkipSpanContextForPatterns: []string{`order\.[0-9a-f]+\.created`},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	assert.True(t, logRecords[0].TraceID().IsEmpty(), "Expected span context to be skipped for the high-cardinality name")
+	assert.Equal(t, span.TraceID(), logRecords[1].TraceID())
+}
+
+// TestUnspecifiedSeverityText tests the severity text set when the severity number is unspecified
+func TestUnspecifiedSeverityText(t *testing.T) {
+	empty := ""
+	literal := "unspecified"
+	tests := []struct {
+		name                    string
+		unspecifiedSeverityText *string
+		severityNumber          int64
+		expectedText            string
+	}{
+		{"Not configured keeps the resolved text", nil, 0, "info"},
+		{"Empty text", &empty, 0, ""},
+		{"Literal text", &literal, 0, "unspecified"},
+		{"Specified severity is unaffected", &literal, int64(plog.SeverityNumberWarn), "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes().PutInt("severity.number", tt.severityNumber)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				UnspecifiedSeverityText: tt.unspecifiedSeverityText,
+				AttributeMappings: config.AttributeMappings{
+					SeverityNumber: "severity.number",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, plog.SeverityNumber(tt.severityNumber), logRecords[0].SeverityNumber())
+			assert.Equal(t, tt.expectedText, logRecords[0].SeverityText())
+		})
+	}
+}
+
+// TestAnnotateScopeAttributeCount tests that the source scope's attribute count is recorded
+func TestAnnotateScopeAttributeCount(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	scopeAttrs := traces.ResourceSpans().At(0).ScopeSpans().At(0).Scope().Attributes()
+	scopeAttrs.PutStr("library.language", "go")
+	scopeAttrs.PutStr("library.variant", "contrib")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AnnotateScopeAttributeCount: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	count, exists := collectLogRecords(logsSink)[0].Attributes().Get("otel.scope.attribute_count")
+	require.True(t, exists, "Expected otel.scope.attribute_count attribute to exist")
+	assert.Equal(t, int64(2), count.Int())
+}
+
+// TestSpanNamePatterns tests that only events from spans with matching names are converted
+func TestSpanNamePatterns(t *testing.T) {
+	tests := []struct {
+		name           string
+		patterns       []string
+		expectedBodies []string
+	}{
+		{"No patterns converts all spans", nil, []string{"api-event", "health-event"}},
+		{"Matching span only", []string{"GET /api/.*"}, []string{"api-event"}},
+		{"Pattern must match the entire name", []string{"/api/.*"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("api-event")
+			scopeSpans := traces.ResourceSpans().At(0).ScopeSpans().At(0)
+			scopeSpans.Spans().At(0).SetName("GET /api/orders")
+			healthSpan := scopeSpans.Spans().AppendEmpty()
+			healthSpan.SetName("GET /healthz")
+			healthSpan.Events().AppendEmpty().SetName("health-event")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SpanNamePatterns: tt.patterns,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestPreserveOriginalSeverityText tests that the pre-canonicalization severity text is preserved
+func TestPreserveOriginalSeverityText(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           config.Config
+		severityAttr     string
+		expectedText     string
+		expectedOriginal string
+		expectPreserved  bool
+	}{
+		{
+			name:             "Severity attribute",
+			config:           config.Config{SeverityAttribute: "level"},
+			severityAttr:     "WARNING",
+			expectedText:     "warn",
+			expectedOriginal: "WARNING",
+			expectPreserved:  true,
+		},
+		{
+			name:             "Attribute mapping",
+			config:           config.Config{AttributeMappings: config.AttributeMappings{SeverityText: "level"}},
+			severityAttr:     "INFO",
+			expectedText:     "info",
+			expectedOriginal: "INFO",
+			expectPreserved:  true,
+		},
+		{
+			name:            "Severity not read from an attribute",
+			config:          config.Config{SeverityByEventName: map[string]string{"test-event": "error"}},
+			severityAttr:    "INFO",
+			expectedText:    "error",
+			expectPreserved: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes().PutStr("level", tt.severityAttr)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := tt.config
+			cfg.PreserveOriginalSeverityText = "severity.original"
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecord := collectLogRecords(logsSink)[0]
+			assert.Equal(t, tt.expectedText, logRecord.SeverityText())
+			original, exists := logRecord.Attributes().Get("severity.original")
+			require.Equal(t, tt.expectPreserved, exists)
+			if exists {
+				assert.Equal(t, tt.expectedOriginal, original.Str())
+			}
+		})
+	}
+}
+
+// TestAggregateExceptions tests combining a span's exception events into a single error record
+func TestAggregateExceptions(t *testing.T) {
+	traces := createTestTracesWithEventNames("exception", "checkpoint", "exception")
+	events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+	events.At(0).Attributes().PutStr("exception.type", "RequestError")
+	events.At(0).Attributes().PutStr("exception.message", "request failed")
+	events.At(2).Attributes().PutStr("exception.type", "ConnectionError")
+	events.At(2).Attributes().PutStr("exception.message", "connection reset")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AggregateExceptions: true,
+		SeverityByEventName: map[string]string{"exception": "error"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	assert.Equal(t, []string{"request failed", "checkpoint"}, collectLogBodies(logsSink))
+	assert.Equal(t, plog.SeverityNumberError, logRecords[0].SeverityNumber())
+
+	causes, exists := logRecords[0].Attributes().Get("exception.causes")
+	require.True(t, exists, "Expected exception.causes attribute to exist")
+	require.Equal(t, 1, causes.Slice().Len())
+	cause := causes.Slice().At(0).Map()
+	causeType, _ := cause.Get("exception.type")
+	causeMessage, _ := cause.Get("exception.message")
+	assert.Equal(t, "ConnectionError", causeType.Str())
+	assert.Equal(t, "connection reset", causeMessage.Str())
+}
+
+// TestSchemaVersionAttribute tests that the configured schema version is stamped on each record
+func TestSchemaVersionAttribute(t *testing.T) {
+	traces := createTestTracesWithEventNames("first", "second")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SchemaVersionAttribute: "spaneventtolog.schema_version",
+		SchemaVersion:          "2",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	for _, logRecord := range logRecords {
+		version, exists := logRecord.Attributes().Get("spaneventtolog.schema_version")
+		require.True(t, exists, "Expected spaneventtolog.schema_version attribute to exist")
+		assert.Equal(t, "2", version.Str())
+	}
+}
+
+// TestGenerateRecordID tests that record IDs are deterministic across runs and unique per event
+func TestGenerateRecordID(t *testing.T) {
+	runIDs := func() []string {
+		// Two events with the same name are distinguished by their index
+		traces := createTestTracesWithEventNames("retry", "retry", "done")
+
+		logsSink := new(consumertest.LogsSink)
+		cfg := config.Config{
+			GenerateRecordID: true,
+		}
+		settings := createTestConnectorSettings(t)
+		connector, err := newConnector(settings, cfg, logsSink)
+		require.NoError(t, err)
+
+		err = connector.ConsumeTraces(context.Background(), traces)
+		require.NoError(t, err)
+
+		var ids []string
+		for _, logRecord := range collectLogRecords(logsSink) {
+			id, exists := logRecord.Attributes().Get("log.record.id")
+			require.True(t, exists, "Expected log.record.id attribute to exist")
+			ids = append(ids, id.Str())
+		}
+		return ids
+	}
+
+	first := runIDs()
+	require.Len(t, first, 3)
+	assert.Equal(t, first, runIDs(), "Expected record IDs to be stable across runs")
+	assert.Len(t, first[0], 32)
+	assert.NotEqual(t, first[0], first[1])
+	assert.NotEqual(t, first[1], first[2])
+}
+
+// TestEmitFullyFilteredSpanLog tests that a debug record is emitted for spans whose events were all filtered
+func TestEmitFullyFilteredSpanLog(t *testing.T) {
+	traces := createTestTracesWithEventNames("cache.hit", "cache.miss")
+	scopeSpans := traces.ResourceSpans().At(0).ScopeSpans().At(0)
+	keptSpan := scopeSpans.Spans().AppendEmpty()
+	keptSpan.Events().AppendEmpty().SetName("exception")
+	scopeSpans.Spans().AppendEmpty().SetName("span-without-events")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEventNames:        []string{"exception"},
+		EmitFullyFilteredSpanLog: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 2)
+	assert.Equal(t, "all events filtered", logRecords[0].Body().Str())
+	assert.Equal(t, plog.SeverityNumberDebug, logRecords[0].SeverityNumber())
+	filtered, exists := logRecords[0].Attributes().Get("spaneventtolog.filtered_events")
+	require.True(t, exists, "Expected spaneventtolog.filtered_events attribute to exist")
+	assert.Equal(t, int64(2), filtered.Int())
+	assert.Equal(t, "exception", logRecords[1].Body().Str())
+}
+
+// TestBodySliceJoin tests joining a string slice body attribute into a single body line
+func TestBodySliceJoin(t *testing.T) {
+	tests := []struct {
+		name         string
+		setBody      func(pcommon.Slice)
+		expectedBody string
+	}{
+		{
+			name: "String slice is joined",
+			setBody: func(s pcommon.Slice) {
+				s.AppendEmpty().SetStr("connection refused")
+				s.AppendEmpty().SetStr("retrying")
+			},
+			expectedBody: "connection refused | retrying",
+		},
+		{
+			name: "Non-string slice falls back to event name, not the secondary attribute",
+			setBody: func(s pcommon.Slice) {
+				s.AppendEmpty().SetStr("connection refused")
+				s.AppendEmpty().SetInt(3)
+			},
+			expectedBody: "test-event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			tt.setBody(event.Attributes().PutEmptySlice("event.body"))
+			event.Attributes().PutStr("message", "secondary")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				BodySliceJoin:          " | ",
+				SecondaryBodyAttribute: "message",
+				AttributeMappings: config.AttributeMappings{
+					Body: "event.body",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []string{tt.expectedBody}, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestBodyBytesEncoding tests that a bytes body attribute is encoded into the log record body when configured
+func TestBodyBytesEncoding(t *testing.T) {
+	tests := []struct {
+		name         string
+		encoding     string
+		expectedBody string
+	}{
+		{
+			name:         "Base64",
+			encoding:     "base64",
+			expectedBody: "3q2+7w==",
+		},
+		{
+			name:         "Hex",
+			encoding:     "hex",
+			expectedBody: "deadbeef",
+		},
+		{
+			name:         "No encoding falls back to event name",
+			expectedBody: "test-event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutEmptyBytes("event.body").FromRaw([]byte{0xde, 0xad, 0xbe, 0xef})
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				BodyBytesEncoding: tt.encoding,
+				AttributeMappings: config.AttributeMappings{
+					Body: "event.body",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, []string{tt.expectedBody}, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestTTLAttribute tests that events older than their TTL are dropped
+func TestTTLAttribute(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		age        time.Duration
+		setTTL     func(pcommon.Map)
+		expectKept bool
+	}{
+		{"Fresh event", 10 * time.Second, func(m pcommon.Map) { m.PutInt("ttl_seconds", 60) }, true},
+		{"Expired event", 2 * time.Minute, func(m pcommon.Map) { m.PutInt("ttl_seconds", 60) }, false},
+		{"Fractional TTL", 1500 * time.Millisecond, func(m pcommon.Map) { m.PutDouble("ttl_seconds", 1.2) }, false},
+		{"No TTL attribute", 24 * time.Hour, func(pcommon.Map) {}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("test-event")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.SetTimestamp(pcommon.NewTimestampFromTime(now.Add(-tt.age)))
+			tt.setTTL(event.Attributes())
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				TTLAttribute: "ttl_seconds",
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+			connector.now = func() time.Time { return now }
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectKept, len(collectLogRecords(logsSink)) == 1)
+		})
+	}
+}
+
+// TestSeverityBySpanKind tests the low-precedence severity derived from the parent span kind
+func TestSeverityBySpanKind(t *testing.T) {
+	tests := []struct {
+		name             string
+		eventName        string
+		spanKind         ptrace.SpanKind
+		expectedSeverity plog.SeverityNumber
+	}{
+		{"Producer span", "publish.failed", ptrace.SpanKindProducer, plog.SeverityNumberWarn},
+		{"Consumer span", "message.received", ptrace.SpanKindConsumer, plog.SeverityNumberInfo},
+		{"Unmapped span kind keeps the default", "started", ptrace.SpanKindInternal, plog.SeverityNumberInfo},
+		{"Event name takes precedence", "exception", ptrace.SpanKindProducer, plog.SeverityNumberError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames(tt.eventName)
+			traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetKind(tt.spanKind)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByEventName: map[string]string{"exception": "error"},
+				SeverityBySpanKind: map[string]string{
+					"producer": "warn",
+					"consumer": "info",
+				},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedSeverity, logRecords[0].SeverityNumber())
+		})
+	}
+}
+
+// TestExcludeEventNames tests that excluded event names are skipped
+func TestExcludeEventNames(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         config.Config
+		expectedBodies []string
+	}{
+		{
+			name: "Exclude without include",
+			config: config.Config{
+				ExcludeEventNames: []string{"message", "cache.hit"},
+			},
+			expectedBodies: []string{"exception", "db.query"},
+		},
+		{
+			name: "Exclude wins over include patterns",
+			config: config.Config{
+				IncludeEventNamePatterns: []string{".*"},
+				ExcludeEventNames:        []string{"message", "cache.hit"},
+			},
+			expectedBodies: []string{"exception", "db.query"},
+		},
+		{
+			name: "Exclude combined with include",
+			config: config.Config{
+				IncludeEventNames: []string{"exception", "db.query"},
+				ExcludeEventNames: []string{"message"},
+			},
+			expectedBodies: []string{"exception", "db.query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("message", "exception", "cache.hit", "db.query")
+
+			logsSink := new(consumertest.LogsSink)
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, tt.config, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestAppendLogs tests appending extracted records into a pre-populated plog.Logs
+func TestAppendLogs(t *testing.T) {
+	dst := plog.NewLogs()
+	existing := dst.ResourceLogs().AppendEmpty()
+	existing.Resource().Attributes().PutStr("service.name", "existing-service")
+	existing.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("existing record")
+
+	cfg := config.Config{
+		LogAttributesFrom: []string{"resource.attributes"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, new(consumertest.LogsSink))
+	require.NoError(t, err)
+
+	connector.AppendLogs(context.Background(), createTestTracesWithEventNames("first", "second"), dst)
+
+	require.Equal(t, 3, dst.LogRecordCount())
+	firstResource := dst.ResourceLogs().At(0)
+	serviceName, _ := firstResource.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "existing-service", serviceName.Str())
+	assert.Equal(t, 1, firstResource.ScopeLogs().At(0).LogRecords().Len())
+	assert.Equal(t, "existing record", firstResource.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+	// ExtractLogs returns the same records in a fresh plog.Logs
+	logs := connector.ExtractLogs(context.Background(), createTestTracesWithEventNames("first", "second"))
+	assert.Equal(t, 2, logs.LogRecordCount())
+}
+
+// TestIncludeSpanKinds tests that only events from spans of the included kinds are converted
+func TestIncludeSpanKinds(t *testing.T) {
+	tests := []struct {
+		name           string
+		kinds          []string
+		expectedBodies []string
+	}{
+		{"No kinds converts all spans", nil, []string{"server-event", "client-event", "consumer-event"}},
+		{"Server and consumer only", []string{"server", "consumer"}, []string{"server-event", "consumer-event"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			for _, kind := range []ptrace.SpanKind{ptrace.SpanKindServer, ptrace.SpanKindClient, ptrace.SpanKindConsumer} {
+				span := spans.AppendEmpty()
+				span.SetKind(kind)
+				span.Events().AppendEmpty().SetName(spanKindName(kind) + "-event")
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanKinds: tt.kinds,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestScopeByAttribute tests grouping records into output scopes named after an attribute value
+func TestScopeByAttribute(t *testing.T) {
+	traces := createTestTracesWithEventNames("order.created", "order.paid", "user.login", "checkpoint")
+	traces.ResourceSpans().At(0).Resource().Attributes().PutStr("business.unit", "platform")
+	events := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events()
+	events.At(0).Attributes().PutStr("business.unit", "orders")
+	events.At(1).Attributes().PutStr("business.unit", "orders")
+	events.At(2).Attributes().PutStr("business.unit", "identity")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		ScopeByAttribute: "business.unit",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	bodiesByScope := map[string][]string{}
+	rls := allLogs[0].ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				bodiesByScope[sl.Scope().Name()] = append(bodiesByScope[sl.Scope().Name()], sl.LogRecords().At(k).Body().Str())
+			}
+		}
+	}
+
+	// The event attribute wins; the resource attribute is the fallback
+	assert.Equal(t, map[string][]string{
+		"orders":   {"order.created", "order.paid"},
+		"identity": {"user.login"},
+		"platform": {"checkpoint"},
+	}, bodiesByScope)
+}
+
+// TestOutputScopeVersionFromAttribute tests that the output scope version is taken from the event or resource attribute
+func TestOutputScopeVersionFromAttribute(t *testing.T) {
+	traces := createTestTracesWithEventNames("deploy.started", "deploy.canary", "deploy.finished")
+	scopeSpans := traces.ResourceSpans().At(0).ScopeSpans().At(0)
+	scopeSpans.Scope().SetVersion("0.1.0")
+	traces.ResourceSpans().At(0).Resource().Attributes().PutStr("service.version", "1.4.0")
+	events := scopeSpans.Spans().At(0).Events()
+	events.At(1).Attributes().PutStr("service.version", "1.5.0-canary")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		OutputScopeVersionFromAttribute: "service.version",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	bodiesByVersion := map[string][]string{}
+	sls := allLogs[0].ResourceLogs().At(0).ScopeLogs()
+	for i := 0; i < sls.Len(); i++ {
+		sl := sls.At(i)
+		assert.Equal(t, "test-scope", sl.Scope().Name(), "Scope name should be kept")
+		for j := 0; j < sl.LogRecords().Len(); j++ {
+			bodiesByVersion[sl.Scope().Version()] = append(bodiesByVersion[sl.Scope().Version()], sl.LogRecords().At(j).Body().Str())
+		}
+	}
+
+	// The event attribute wins; the resource attribute is the fallback
+	assert.Equal(t, map[string][]string{
+		"1.4.0":        {"deploy.started", "deploy.finished"},
+		"1.5.0-canary": {"deploy.canary"},
+	}, bodiesByVersion)
+}
+
+// TestIncludeSpanLinkCount tests that the number of span links is recorded with the span context
+func TestIncludeSpanLinkCount(t *testing.T) {
+	traces := createTestTracesWithStructuredEvent()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	span.Links().AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}))
+	span.Links().AppendEmpty().SetSpanID(pcommon.SpanID([8]byte{1, 1, 2, 3, 5, 8, 13, 21}))
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeSpanContext:   true,
+		IncludeSpanLinkCount: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	linkCount, exists := collectLogRecords(logsSink)[0].Attributes().Get("span.link_count")
+	require.True(t, exists, "Expected span.link_count attribute to exist")
+	assert.Equal(t, int64(2), linkCount.Int())
+}
+
+// TestIncludeSpanStatuses tests that only events from spans with the included statuses are converted
+func TestIncludeSpanStatuses(t *testing.T) {
+	tests := []struct {
+		name           string
+		statuses       []string
+		expectedBodies []string
+	}{
+		{"No statuses converts all spans", nil, []string{"unset-event", "ok-event", "error-event"}},
+		{"Error only", []string{"error"}, []string{"error-event"}},
+		{"Unset and ok", []string{"unset", "ok"}, []string{"unset-event", "ok-event"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			for _, status := range []struct {
+				code      ptrace.StatusCode
+				eventName string
+			}{
+				{ptrace.StatusCodeUnset, "unset-event"},
+				{ptrace.StatusCodeOk, "ok-event"},
+				{ptrace.StatusCodeError, "error-event"},
+			} {
+				span := spans.AppendEmpty()
+				span.Status().SetCode(status.code)
+				span.Events().AppendEmpty().SetName(status.eventName)
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeSpanStatuses: tt.statuses,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+// TestRequireSpanAttributes tests that only events from spans carrying the required attributes are converted
+func TestRequireSpanAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		required       map[string]string
+		expectedBodies []string
+	}{
+		{"Matching value", map[string]string{"tenant.tier": "premium"}, []string{"premium-event"}},
+		{"Presence only", map[string]string{"tenant.tier": ""}, []string{"premium-event", "basic-event"}},
+		{"All attributes must match", map[string]string{"tenant.tier": "premium", "tenant.region": "eu"}, nil},
+		{"Missing attribute skips the span", map[string]string{"tenant.id": ""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			premiumSpan := spans.AppendEmpty()
+			premiumSpan.Attributes().PutStr("tenant.tier", "premium")
+			premiumSpan.Attributes().PutStr("tenant.region", "us")
+			premiumSpan.Events().AppendEmpty().SetName("premium-event")
+			basicSpan := spans.AppendEmpty()
+			basicSpan.Attributes().PutStr("tenant.tier", "basic")
+			basicSpan.Events().AppendEmpty().SetName("basic-event")
+			spans.AppendEmpty().Events().AppendEmpty().SetName("untagged-event")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				RequireSpanAttributes: tt.required,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+func TestMaxTotalAttributes(t *testing.T) {
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	for _, name := range []string{"first", "second", "third"} {
+		event := span.Events().AppendEmpty()
+		event.SetName(name)
+		event.Attributes().PutStr("a", "1")
+		event.Attributes().PutStr("b", "2")
+		event.Attributes().PutStr("c", "3")
+	}
+
+	reader := sdkmetric.NewManualReader()
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:  []string{"event.attributes"},
+		MaxTotalAttributes: 5,
+	}
+	settings := createTestConnectorSettings(t)
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	expectedLens := []int{3, 2, 0}
+	expectedDropped := []uint32{0, 1, 3}
+	for i, logRecord := range logRecords {
+		assert.Equal(t, expectedLens[i], logRecord.Attributes().Len())
+		assert.Equal(t, expectedDropped[i], logRecord.DroppedAttributesCount())
+		// Bodies and severities are kept regardless of the cap
+		assert.NotEmpty(t, logRecord.Body().Str())
+		assert.Equal(t, plog.SeverityNumberInfo, logRecord.SeverityNumber())
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	metric := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "spaneventtolog.truncated_attributes", metric.Name)
+	sum, ok := metric.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(4), sum.DataPoints[0].Value)
+}
+
+func TestMaxTotalAttributesCoversEveryAttribute(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          config.Config
+		expectedAttrs   []map[string]any
+		expectedDropped []uint32
+	}{
+		{
+			name: "sequence attributes",
+			config: config.Config{
+				LogAttributesFrom:       []string{"event.attributes"},
+				SequenceAttribute:       "seq",
+				IncludeEmissionSequence: true,
+			},
+			expectedAttrs:   []map[string]any{{"exception.message": "boom", "exception.type": "Error"}, {}},
+			expectedDropped: []uint32{2, 4},
+		},
+		{
+			name: "exception causes",
+			config: config.Config{
+				LogAttributesFrom:   []string{"event.attributes"},
+				AggregateExceptions: true,
+			},
+			// The causes slice is removed from the primary record, and the later cause dropped
+			expectedAttrs:   []map[string]any{{"exception.message": "boom", "exception.type": "Error"}},
+			expectedDropped: []uint32{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			for i := 0; i < 2; i++ {
+				event := span.Events().AppendEmpty()
+				event.SetName("exception")
+				event.Attributes().PutStr("exception.message", "boom")
+				event.Attributes().PutStr("exception.type", "Error")
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := tt.config
+			cfg.MaxTotalAttributes = 2
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, len(tt.expectedAttrs))
+			for i, logRecord := range logRecords {
+				assert.Equal(t, tt.expectedAttrs[i], logRecord.Attributes().AsRaw())
+				assert.Equal(t, tt.expectedDropped[i], logRecord.DroppedAttributesCount())
+			}
+		})
+	}
+}
+
+func TestShortenExceptionType(t *testing.T) {
+	tests := []struct {
+		name          string
+		exceptionType string
+		expectedShort string
+	}{
+		{"Fully-qualified type", "com.example.foo.NullPointerException", "NullPointerException"},
+		{"Unqualified type", "ValueError", "ValueError"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTraces()
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutStr("exception.type", tt.exceptionType)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeEventNames:    []string{"exception"},
+				LogAttributesFrom:    []string{"event.attributes"},
+				ShortenExceptionType: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			attrs := logRecords[0].Attributes()
+
+			short, exists := attrs.Get("error.type.short")
+			require.True(t, exists, "Expected error.type.short attribute to exist")
+			assert.Equal(t, tt.expectedShort, short.Str())
+
+			full, exists := attrs.Get("exception.type")
+			require.True(t, exists, "Expected exception.type attribute to be kept")
+			assert.Equal(t, tt.exceptionType, full.Str())
+		})
+	}
+}
+
+func TestRequireEventAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		required       map[string]string
+		expectedBodies []string
+	}{
+		{"Bool and string values match", map[string]string{"log.emit": "true"}, []string{"bool-event", "string-event"}},
+		{"Int value matches", map[string]string{"log.level": "3"}, []string{"int-event"}},
+		{"Double value matches", map[string]string{"log.ratio": "0.50"}, []string{"double-event"}},
+		{"Mismatched value skips the event", map[string]string{"log.emit": "false"}, nil},
+		{"All attributes must match", map[string]string{"log.emit": "true", "log.level": "3"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			events := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events()
+			boolEvent := events.AppendEmpty()
+			boolEvent.SetName("bool-event")
+			boolEvent.Attributes().PutBool("log.emit", true)
+			stringEvent := events.AppendEmpty()
+			stringEvent.SetName("string-event")
+			stringEvent.Attributes().PutStr("log.emit", "true")
+			intEvent := events.AppendEmpty()
+			intEvent.SetName("int-event")
+			intEvent.Attributes().PutInt("log.level", 3)
+			doubleEvent := events.AppendEmpty()
+			doubleEvent.SetName("double-event")
+			doubleEvent.Attributes().PutDouble("log.ratio", 0.5)
+			events.AppendEmpty().SetName("untagged-event")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				RequireEventAttributes: tt.required,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedBodies, collectLogBodies(logsSink))
+		})
+	}
+}
+
+func TestHeartbeatMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEventNames: []string{"exception"},
+		HeartbeatMetric:   true,
+		HeartbeatInterval: 10 * time.Millisecond,
+	}
+	settings := createTestConnectorSettings(t)
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	require.NoError(t, connector.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, connector.ConsumeTraces(context.Background(), createTestTraces()))
+
+	metricValues := func() map[string]int64 {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		values := make(map[string]int64)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch data := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					values[m.Name] = data.DataPoints[0].Value
+				case metricdata.Gauge[int64]:
+					values[m.Name] = data.DataPoints[0].Value
+				}
+			}
+		}
+		return values
+	}
+
+	assert.Eventually(t, func() bool {
+		values := metricValues()
+		return values["spaneventtolog.heartbeats"] >= 2 && values["spaneventtolog.heartbeat.events_processed"] == 1
+	}, time.Second, 5*time.Millisecond, "Expected heartbeats reporting the processed event")
+
+	require.NoError(t, connector.Shutdown(context.Background()))
+
+	// No heartbeats are recorded after shutdown
+	stopped := metricValues()["spaneventtolog.heartbeats"]
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, metricValues()["spaneventtolog.heartbeats"])
+}
+
+func TestLog4jSeverityMode(t *testing.T) {
+	tests := []struct {
+		level          string
+		log4jMode      bool
+		expectedNumber plog.SeverityNumber
+		expectedText   string
+	}{
+		{"ALL", true, plog.SeverityNumberTrace, "trace"},
+		{"TRACE", true, plog.SeverityNumberTrace, "trace"},
+		{"DEBUG", true, plog.SeverityNumberDebug, "debug"},
+		{"INFO", true, plog.SeverityNumberInfo, "info"},
+		{"WARN", true, plog.SeverityNumberWarn, "warn"},
+		{"ERROR", true, plog.SeverityNumberError, "error"},
+		{"FATAL", true, plog.SeverityNumberFatal, "fatal"},
+		{"OFF", true, plog.SeverityNumberFatal4, "fatal4"},
+		// Without the mode, Java-specific levels fall back to the default severity
+		{"OFF", false, plog.SeverityNumberInfo, "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/log4j=%t", tt.level, tt.log4jMode), func(t *testing.T) {
+			traces := createTestTracesWithEventNames("log")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutStr("log.level", tt.level)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityAttribute: "log.level",
+				Log4jSeverityMode: tt.log4jMode,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+			assert.Equal(t, tt.expectedText, logRecords[0].SeverityText())
+		})
+	}
+}
+
+func TestDefaultSeverity(t *testing.T) {
+	tests := []struct {
+		name            string
+		defaultSeverity string
+		expectedNumber  plog.SeverityNumber
+		expectedText    string
+	}{
+		{"Unset keeps info", "", plog.SeverityNumberInfo, "info"},
+		{"Configured default", "debug", plog.SeverityNumberDebug, "debug"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("unmapped", "exception")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByEventName: map[string]string{"exception": "error"},
+				DefaultSeverity:     tt.defaultSeverity,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 2)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+			assert.Equal(t, tt.expectedText, logRecords[0].SeverityText())
+
+			// Mapped events are unaffected by the default
+			assert.Equal(t, plog.SeverityNumberError, logRecords[1].SeverityNumber())
+		})
+	}
+}
+
+func TestAnnotateSourceIndex(t *testing.T) {
+	traces := ptrace.NewTraces()
+	for _, names := range [][]string{{"first"}, {"second", "third"}} {
+		spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for _, name := range names {
+			spans.AppendEmpty().Events().AppendEmpty().SetName(name)
+		}
+	}
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		AnnotateSourceIndex: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	expected := map[string][2]int64{
+		"first":  {0, 0},
+		"second": {1, 0},
+		"third":  {1, 1},
+	}
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, len(expected))
+	for _, logRecord := range logRecords {
+		indices := expected[logRecord.Body().Str()]
+		resourceIndex, exists := logRecord.Attributes().Get("spaneventtolog.source_resource_index")
+		require.True(t, exists, "Expected spaneventtolog.source_resource_index attribute to exist")
+		assert.Equal(t, indices[0], resourceIndex.Int())
+		spanIndex, exists := logRecord.Attributes().Get("spaneventtolog.source_span_index")
+		require.True(t, exists, "Expected spaneventtolog.source_span_index attribute to exist")
+		assert.Equal(t, indices[1], spanIndex.Int())
+	}
+}
+
+func TestSeverityNumberAttributeTypes(t *testing.T) {
+	tests := []struct {
+		name           string
+		setValue       func(attrs pcommon.Map)
+		expectedNumber plog.SeverityNumber
+		expectedText   string
+	}{
+		{"Int", func(attrs pcommon.Map) { attrs.PutInt("event.severity_number", 17) }, plog.SeverityNumberError, "error"},
+		{"String", func(attrs pcommon.Map) { attrs.PutStr("event.severity_number", "9") }, plog.SeverityNumberInfo, "info"},
+		{"Double is truncated", func(attrs pcommon.Map) { attrs.PutDouble("event.severity_number", 13.7) }, plog.SeverityNumberWarn, "warn"},
+		// Unparseable values fall back to the event name mapping
+		{"Invalid string", func(attrs pcommon.Map) { attrs.PutStr("event.severity_number", "high") }, plog.SeverityNumberFatal, "fatal"},
+		{"Unsupported type", func(attrs pcommon.Map) { attrs.PutBool("event.severity_number", true) }, plog.SeverityNumberFatal, "fatal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("crash")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			tt.setValue(event.Attributes())
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				AttributeMappings: config.AttributeMappings{
+					SeverityNumber: "event.severity_number",
+				},
+				SeverityByEventName: map[string]string{"crash": "fatal"},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+			assert.Equal(t, tt.expectedText, logRecords[0].SeverityText())
+		})
+	}
+}
+
+func TestSemConvMode(t *testing.T) {
+	traces := createTestTracesWithEventNames("request")
+	event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+	event.Attributes().PutStr("http.method", "GET")
+	event.Attributes().PutInt("http.status_code", 503)
+	event.Attributes().PutStr("http.url", "https://example.com/orders")
+	event.Attributes().PutStr("http.user_agent", "curl/8.0")
+	event.Attributes().PutStr("url.full", "https://example.com/orders?id=1")
+	event.Attributes().PutStr("db.statement", "SELECT 1")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes"},
+		SemConvMode:       "http",
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 1)
+	assert.Equal(t, map[string]any{
+		"http.request.method":       "GET",
+		"http.response.status_code": int64(503),
+		// An attribute already under the semantic-convention key is kept
+		"url.full":            "https://example.com/orders?id=1",
+		"user_agent.original": "curl/8.0",
+		// Keys outside the mode are copied verbatim
+		"db.statement": "SELECT 1",
+	}, logRecords[0].Attributes().AsRaw())
+}
+
+func TestSeverityAliases(t *testing.T) {
+	tests := []struct {
+		level          string
+		expectedNumber plog.SeverityNumber
+		expectedText   string
+	}{
+		{"notice", plog.SeverityNumberInfo3, "info3"},
+		{"CRITICAL", plog.SeverityNumberFatal2, "fatal2"},
+		// Built-in levels still resolve alongside the aliases
+		{"warn", plog.SeverityNumberWarn, "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("log")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutStr("log.level", tt.level)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityAttribute: "log.level",
+				SeverityAliases:   map[string]string{"notice": "info3", "critical": "fatal2"},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+			assert.Equal(t, tt.expectedText, logRecords[0].SeverityText())
+		})
+	}
+}
+
+func TestSeverityFromSpanStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      ptrace.StatusCode
+		statusMessage   string
+		eventName       string
+		expectedNumber  plog.SeverityNumber
+		expectedMessage string
+	}{
+		{"Error status", ptrace.StatusCodeError, "upstream timeout", "retry", plog.SeverityNumberError, "upstream timeout"},
+		{"Ok status", ptrace.StatusCodeOk, "", "retry", plog.SeverityNumberInfo, ""},
+		{"Unset status keeps the fallback", ptrace.StatusCodeUnset, "", "retry", plog.SeverityNumberDebug, ""},
+		{"Event name mapping takes precedence", ptrace.StatusCodeError, "", "checkpoint", plog.SeverityNumberWarn, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames(tt.eventName)
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.Status().SetCode(tt.statusCode)
+			span.Status().SetMessage(tt.statusMessage)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				SeverityByEventName:        map[string]string{"checkpoint": "warn"},
+				SeverityFromSpanStatus:     true,
+				SeverityForUnmatchedEvents: "debug",
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+
+			message, exists := logRecords[0].Attributes().Get("span.status_message")
+			assert.Equal(t, tt.expectedMessage != "", exists, "Unexpected presence of span.status_message")
+			if exists {
+				assert.Equal(t, tt.expectedMessage, message.Str())
+			}
+		})
+	}
+}
+
+func TestPreserveEventAsMap(t *testing.T) {
+	traces := createTestTracesWithEventNames("order.created")
+	event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+	event.SetTimestamp(pcommon.Timestamp(1700000000000000000))
+	event.SetDroppedAttributesCount(2)
+	event.Attributes().PutStr("order.id", "o-1")
+	items := event.Attributes().PutEmptySlice("order.items")
+	items.AppendEmpty().SetStr("book")
+	items.AppendEmpty().SetStr("pen")
+	event.Attributes().PutEmptyMap("order.customer").PutInt("id", 42)
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		PreserveEventAsMap: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 1)
+	preserved, exists := logRecords[0].Attributes().Get("span.event")
+	require.True(t, exists, "Expected span.event attribute to exist")
+	require.Equal(t, pcommon.ValueTypeMap, preserved.Type())
+	assert.Equal(t, map[string]any{
+		"name":           "order.created",
+		"time_unix_nano": int64(1700000000000000000),
+		"attributes": map[string]any{
+			"order.id":       "o-1",
+			"order.items":    []any{"book", "pen"},
+			"order.customer": map[string]any{"id": int64(42)},
+		},
+		"dropped_attributes_count": int64(2),
+	}, preserved.Map().AsRaw())
+}
+
+func TestDowngradeSeverities(t *testing.T) {
+	traces := createTestTracesWithEventNames("crash", "exception", "checkpoint")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		SeverityByEventName: map[string]string{"crash": "fatal", "exception": "error"},
+		DowngradeSeverities: map[string]string{"fatal": "error"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	var severities []string
+	for _, logRecord := range logRecords {
+		severities = append(severities, logRecord.SeverityText())
+	}
+	// Fatal is downgraded to error; other severities are kept
+	assert.Equal(t, []string{"error", "error", "info"}, severities)
+	assert.Equal(t, plog.SeverityNumberError, logRecords[0].SeverityNumber())
+}
+
+func TestSpanContextSetsTraceFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		spanFlags       uint32
+		expectedSampled bool
+	}{
+		{"Sampled", 0x01, true},
+		{"Sampled with remote parent bits", spanFlagsHasIsRemote | spanFlagsIsRemote | 0x01, true},
+		{"Not sampled", 0x00, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTraces()
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.SetFlags(tt.spanFlags)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				IncludeEventNames:  []string{"exception"},
+				IncludeSpanContext: true,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedSampled, logRecords[0].Flags().IsSampled())
+			assert.Equal(t, plog.LogRecordFlags(tt.spanFlags&0xff), logRecords[0].Flags())
+		})
+	}
+}
+
+func TestEqualResourcesShareResourceLogs(t *testing.T) {
+	traces := ptrace.NewTraces()
+	first := traces.ResourceSpans().AppendEmpty()
+	first.Resource().Attributes().PutStr("service.name", "checkout")
+	first.Resource().Attributes().PutStr("deployment.environment", "prod")
+	first.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events().AppendEmpty().SetName("first")
+	// Same attributes inserted in a different order
+	second := traces.ResourceSpans().AppendEmpty()
+	second.Resource().Attributes().PutStr("deployment.environment", "prod")
+	second.Resource().Attributes().PutStr("service.name", "checkout")
+	second.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events().AppendEmpty().SetName("second")
+	other := traces.ResourceSpans().AppendEmpty()
+	other.Resource().Attributes().PutStr("service.name", "payments")
+	other.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Events().AppendEmpty().SetName("other")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"resource.attributes"},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	rls := allLogs[0].ResourceLogs()
+	require.Equal(t, 2, rls.Len(), "Expected equal resources to share a ResourceLogs")
+	assert.Equal(t, map[string]any{"service.name": "checkout", "deployment.environment": "prod"}, rls.At(0).Resource().Attributes().AsRaw())
+	mergedRecords := 0
+	for i := 0; i < rls.At(0).ScopeLogs().Len(); i++ {
+		mergedRecords += rls.At(0).ScopeLogs().At(i).LogRecords().Len()
+	}
+	assert.Equal(t, 2, mergedRecords, "Expected both events of the equal resources in one ResourceLogs")
+	assert.Equal(t, map[string]any{"service.name": "payments"}, rls.At(1).Resource().Attributes().AsRaw())
+}
+
+func TestIncludeEmissionSequence(t *testing.T) {
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeEmissionSequence: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	// The sequence keeps increasing across ConsumeTraces calls
+	err = connector.ConsumeTraces(context.Background(), createTestTracesWithEventNames("first", "second"))
+	assert.NoError(t, err)
+	err = connector.ConsumeTraces(context.Background(), createTestTracesWithEventNames("third"))
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 3)
+	for i, logRecord := range logRecords {
+		sequence, exists := logRecord.Attributes().Get("spaneventtolog.emission_seq")
+		require.True(t, exists, "Expected spaneventtolog.emission_seq attribute to exist")
+		assert.Equal(t, int64(i), sequence.Int())
+		assert.Equal(t, []string{"first", "second", "third"}[i], logRecord.Body().Str())
+	}
+}
+
+func TestBodyExtractPattern(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedAttrs map[string]any
+	}{
+		{"Both fields", "user=alice action=login", map[string]any{"message": "user=alice action=login", "user": "alice", "action": "login"}},
+		{"Optional group missing", "user=bob", map[string]any{"message": "user=bob", "user": "bob"}},
+		{"No match", "heartbeat", map[string]any{"message": "heartbeat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("log")
+			event := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0)
+			event.Attributes().PutStr("message", tt.body)
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				LogAttributesFrom:  []string{"event.attributes"},
+				AttributeMappings:  config.AttributeMappings{Body: "message"},
+				BodyExtractPattern: `user=(?P<user>\S+)(?: action=(?P<action>\S+))?`,
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.body, logRecords[0].Body().Str())
+			assert.Equal(t, tt.expectedAttrs, logRecords[0].Attributes().AsRaw())
+		})
+	}
+}
+
+func TestEqualScopesShareScopeLogs(t *testing.T) {
+	traces := ptrace.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	for _, tc := range []struct{ name, version, event string }{
+		{"checkout", "1.0.0", "first"},
+		{"checkout", "1.0.0", "second"},
+		{"checkout", "2.0.0", "other"},
+	} {
+		scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+		scopeSpans.Scope().SetName(tc.name)
+		scopeSpans.Scope().SetVersion(tc.version)
+		scopeSpans.Scope().Attributes().PutStr("library.language", "go")
+		scopeSpans.Spans().AppendEmpty().Events().AppendEmpty().SetName(tc.event)
+	}
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	require.Equal(t, 1, allLogs[0].ResourceLogs().Len())
+	sls := allLogs[0].ResourceLogs().At(0).ScopeLogs()
+	require.Equal(t, 2, sls.Len(), "Expected equal scopes to share a ScopeLogs")
+
+	assert.Equal(t, "1.0.0", sls.At(0).Scope().Version())
+	require.Equal(t, 2, sls.At(0).LogRecords().Len())
+	assert.Equal(t, "first", sls.At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, "second", sls.At(0).LogRecords().At(1).Body().Str())
+
+	assert.Equal(t, "2.0.0", sls.At(1).Scope().Version())
+	require.Equal(t, 1, sls.At(1).LogRecords().Len())
+	assert.Equal(t, "other", sls.At(1).LogRecords().At(0).Body().Str())
+}
+
+func TestSchemaURLs(t *testing.T) {
+	traces := ptrace.NewTraces()
+	for _, tc := range []struct{ resourceSchemaURL, scopeSchemaURL, event string }{
+		{"https://opentelemetry.io/schemas/1.26.0", "https://opentelemetry.io/schemas/1.24.0", "first"},
+		{"https://opentelemetry.io/schemas/1.26.0", "https://opentelemetry.io/schemas/1.24.0", "second"},
+		{"https://opentelemetry.io/schemas/1.21.0", "", "other"},
+	} {
+		resourceSpans := traces.ResourceSpans().AppendEmpty()
+		resourceSpans.SetSchemaUrl(tc.resourceSchemaURL)
+		resourceSpans.Resource().Attributes().PutStr("service.name", "checkout")
+		scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+		scopeSpans.SetSchemaUrl(tc.scopeSchemaURL)
+		scopeSpans.Scope().SetName("checkout")
+		scopeSpans.Spans().AppendEmpty().Events().AppendEmpty().SetName(tc.event)
+	}
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	rls := allLogs[0].ResourceLogs()
+	// Equal resources with different schema URLs are kept apart
+	require.Equal(t, 2, rls.Len())
+
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.26.0", rls.At(0).SchemaUrl())
+	require.Equal(t, 1, rls.At(0).ScopeLogs().Len())
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.24.0", rls.At(0).ScopeLogs().At(0).SchemaUrl())
+	assert.Equal(t, 2, rls.At(0).ScopeLogs().At(0).LogRecords().Len())
+
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.21.0", rls.At(1).SchemaUrl())
+	require.Equal(t, 1, rls.At(1).ScopeLogs().Len())
+	assert.Empty(t, rls.At(1).ScopeLogs().At(0).SchemaUrl())
+}
+
+func TestIncludeNormalizedEventName(t *testing.T) {
+	traces := createTestTracesWithEventNames(" Order.Created ")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		IncludeNormalizedEventName: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 1)
+	assert.Equal(t, " Order.Created ", logRecords[0].Body().Str(), "Expected the body to keep the raw event name")
+	normalized, exists := logRecords[0].Attributes().Get("event.name.normalized")
+	require.True(t, exists, "Expected event.name.normalized attribute to exist")
+	assert.Equal(t, "order.created", normalized.Str())
+}
+
+func TestHonorExplicitSeverityNumberAttribute(t *testing.T) {
+	tests := []struct {
+		name           string
+		honor          bool
+		setValue       func(attrs pcommon.Map)
+		expectedNumber plog.SeverityNumber
+	}{
+		{"Int overrides all sources", true, func(attrs pcommon.Map) { attrs.PutInt("otel.log.severity_number", 5) }, plog.SeverityNumberDebug},
+		{"String overrides all sources", true, func(attrs pcommon.Map) { attrs.PutStr("otel.log.severity_number", "21") }, plog.SeverityNumberFatal},
+		{"Out of range value is ignored", true, func(attrs pcommon.Map) { attrs.PutInt("otel.log.severity_number", 99) }, plog.SeverityNumberWarn},
+		{"Not honored by default", false, func(attrs pcommon.Map) { attrs.PutInt("otel.log.severity_number", 5) }, plog.SeverityNumberWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := createTestTracesWithEventNames("exception")
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			span.SetKind(ptrace.SpanKindServer)
+			event := span.Events().At(0)
+			tt.setValue(event.Attributes())
+			event.Attributes().PutInt("event.severity_number", 17)
+			event.Attributes().PutStr("level", "fatal")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := config.Config{
+				HonorExplicitSeverityNumberAttribute: tt.honor,
+				AttributeMappings: config.AttributeMappings{
+					SeverityNumber: "event.severity_number",
+				},
+				SeverityAttribute:   "level",
+				SeverityByEventName: map[string]string{"exception": "error"},
+			}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+			// The custom resolver is overridden as well
+			connector.severityResolver = spanKindSeverityResolver{}
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedNumber, logRecords[0].SeverityNumber())
+			assert.Equal(t, severityNumberToText(tt.expectedNumber), logRecords[0].SeverityText())
+		})
+	}
+}
+
+func TestAttributeAllowlists(t *testing.T) {
+	tests := []struct {
+		name                  string
+		cfg                   config.Config
+		expectedAttrs         map[string]any
+		expectedResourceAttrs map[string]any
+	}{
+		{
+			name: "Empty allowlists copy every attribute",
+			cfg:  config.Config{},
+			expectedAttrs: map[string]any{
+				"order.id": "o-1", "order.note": "fragile", "http.route": "/orders", "user.email": "alice@example.com",
+			},
+			expectedResourceAttrs: map[string]any{"service.name": "checkout", "host.name": "node-1"},
+		},
+		{
+			name: "Only allowed keys are copied",
+			cfg: config.Config{
+				EventAttributeAllowlist:    []string{"order.id"},
+				SpanAttributeAllowlist:     []string{"http.route"},
+				ResourceAttributeAllowlist: []string{"service.name"},
+			},
+			expectedAttrs:         map[string]any{"order.id": "o-1", "http.route": "/orders"},
+			expectedResourceAttrs: map[string]any{"service.name": "checkout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			resourceSpans := traces.ResourceSpans().AppendEmpty()
+			resourceSpans.Resource().Attributes().PutStr("service.name", "checkout")
+			resourceSpans.Resource().Attributes().PutStr("host.name", "node-1")
+			span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			span.Attributes().PutStr("http.route", "/orders")
+			span.Attributes().PutStr("user.email", "alice@example.com")
+			event := span.Events().AppendEmpty()
+			event.SetName("order.created")
+			event.Attributes().PutStr("order.id", "o-1")
+			event.Attributes().PutStr("order.note", "fragile")
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := tt.cfg
+			cfg.LogAttributesFrom = []string{"event.attributes", "span.attributes", "resource.attributes"}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			allLogs := logsSink.AllLogs()
+			require.Len(t, allLogs, 1)
+			assert.Equal(t, tt.expectedResourceAttrs, allLogs[0].ResourceLogs().At(0).Resource().Attributes().AsRaw())
+			logRecords := collectLogRecords(logsSink)
+			require.Len(t, logRecords, 1)
+			assert.Equal(t, tt.expectedAttrs, logRecords[0].Attributes().AsRaw())
+		})
+	}
+}
+
+func TestAttributeDenylists(t *testing.T) {
+	traces := ptrace.NewTraces()
+	resourceSpans := traces.ResourceSpans().AppendEmpty()
+	resourceSpans.Resource().Attributes().PutStr("service.name", "checkout")
+	resourceSpans.Resource().Attributes().PutStr("cloud.account.id", "123456")
+	span := resourceSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.Attributes().PutStr("http.route", "/orders")
+	span.Attributes().PutStr("http.request.header.authorization", "Bearer secret")
+	event := span.Events().AppendEmpty()
+	event.SetName("order.created")
+	event.Attributes().PutStr("order.id", "o-1")
+	event.Attributes().PutStr("user.password", "hunter2")
+	event.Attributes().PutStr("legacy.password", "hunter3")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom: []string{"event.attributes", "span.attributes", "resource.attributes"},
+		// Deny takes precedence over allow
+		EventAttributeAllowlist:   []string{"order.id", "user.password", "legacy.password"},
+		EventAttributeDenylist:    []string{"user.password"},
+		SpanAttributeDenylist:     []string{"http.request.header.authorization"},
+		ResourceAttributeDenylist: []string{"cloud.account.id"},
+		// Renaming into a denied key doesn't bypass the denylist
+		AttributeRenameRules: []config.AttributeRenameRule{{Pattern: `^legacy\.`, Replacement: "user."}},
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 1)
+	assert.Equal(t, map[string]any{"service.name": "checkout"}, allLogs[0].ResourceLogs().At(0).Resource().Attributes().AsRaw())
+	logRecords := collectLogRecords(logsSink)
+	require.Len(t, logRecords, 1)
+	assert.Equal(t, map[string]any{"order.id": "o-1", "http.route": "/orders"}, logRecords[0].Attributes().AsRaw())
+}
+
+func TestEventAttributeDenylistAppliesToEveryCopy(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.Config
+		check  func(t *testing.T, logRecord plog.LogRecord)
+	}{
+		{
+			name:   "attributes map body",
+			config: config.Config{BodyMode: "attributes_map"},
+			check: func(t *testing.T, logRecord plog.LogRecord) {
+				assert.Equal(t, map[string]any{"exception.message": "boom"}, logRecord.Body().Map().AsRaw())
+			},
+		},
+		{
+			name:   "preserved event",
+			config: config.Config{PreserveEventAsMap: true},
+			check: func(t *testing.T, logRecord plog.LogRecord) {
+				preserved, ok := logRecord.Attributes().Get("span.event")
+				require.True(t, ok)
+				attrs, ok := preserved.Map().Get("attributes")
+				require.True(t, ok)
+				assert.Equal(t, map[string]any{"exception.message": "boom"}, attrs.Map().AsRaw())
+			},
+		},
+		{
+			name: "collapsed JSON",
+			config: config.Config{CollapseAttributesToJSON: config.CollapseAttributesToJSON{
+				TargetKey:  "event.json",
+				SourceKeys: []string{"exception.message", "secret"},
+			}},
+			check: func(t *testing.T, logRecord plog.LogRecord) {
+				collapsed, ok := logRecord.Attributes().Get("event.json")
+				require.True(t, ok)
+				assert.JSONEq(t, `{"exception.message":"boom"}`, collapsed.Str())
+			},
+		},
+		{
+			name:   "exception causes",
+			config: config.Config{AggregateExceptions: true},
+			check: func(t *testing.T, logRecord plog.LogRecord) {
+				causes, ok := logRecord.Attributes().Get("exception.causes")
+				require.True(t, ok)
+				assert.Equal(t, []any{map[string]any{"exception.message": "boom"}}, causes.Slice().AsRaw())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := ptrace.NewTraces()
+			span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			for i := 0; i < 2; i++ {
+				event := span.Events().AppendEmpty()
+				event.SetName("exception")
+				event.Attributes().PutStr("exception.message", "boom")
+				event.Attributes().PutStr("secret", "hunter2")
+			}
+
+			logsSink := new(consumertest.LogsSink)
+			cfg := tt.config
+			cfg.LogAttributesFrom = []string{"event.attributes"}
+			cfg.EventAttributeDenylist = []string{"secret"}
+			settings := createTestConnectorSettings(t)
+			connector, err := newConnector(settings, cfg, logsSink)
+			require.NoError(t, err)
+
+			err = connector.ConsumeTraces(context.Background(), traces)
+			assert.NoError(t, err)
+
+			logRecords := collectLogRecords(logsSink)
+			require.NotEmpty(t, logRecords)
+			_, denied := logRecords[0].Attributes().Get("secret")
+			assert.False(t, denied)
+			tt.check(t, logRecords[0])
+		})
+	}
+}
+
+func TestFlushErrorsImmediately(t *testing.T) {
+	traces := createTestTracesWithEventNames("checkout.started", "exception", "checkout.completed", "crash")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:      []string{"resource.attributes"},
+		SeverityByEventName:    map[string]string{"exception": "error", "crash": "fatal"},
+		FlushErrorsImmediately: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	// Errors are sent first in their own call, and no record is lost
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 2)
+	assert.Equal(t, 4, logsSink.LogRecordCount())
+
+	bodies := make([][]string, len(allLogs))
+	for i, logs := range allLogs {
+		rls := logs.ResourceLogs()
+		for j := 0; j < rls.Len(); j++ {
+			// Records keep their resource and scope
+			assert.Equal(t, map[string]any{"service.name": "test-service"}, rls.At(j).Resource().Attributes().AsRaw())
+			for k := 0; k < rls.At(j).ScopeLogs().Len(); k++ {
+				assert.Equal(t, "test-scope", rls.At(j).ScopeLogs().At(k).Scope().Name())
+				lrs := rls.At(j).ScopeLogs().At(k).LogRecords()
+				for l := 0; l < lrs.Len(); l++ {
+					bodies[i] = append(bodies[i], lrs.At(l).Body().Str())
+				}
+			}
+		}
+	}
+	assert.Equal(t, []string{"exception", "crash"}, bodies[0])
+	assert.Equal(t, []string{"checkout.started", "checkout.completed"}, bodies[1])
+}
+
+// TestFlushErrorsImmediatelyWithResourceEventCount tests that each flushed batch counts only its own records
+func TestFlushErrorsImmediatelyWithResourceEventCount(t *testing.T) {
+	traces := createTestTracesWithEventNames("checkout.started", "exception", "checkout.completed")
+
+	logsSink := new(consumertest.LogsSink)
+	cfg := config.Config{
+		LogAttributesFrom:          []string{"resource.attributes"},
+		SeverityByEventName:        map[string]string{"exception": "error"},
+		FlushErrorsImmediately:     true,
+		AnnotateResourceEventCount: true,
+	}
+	settings := createTestConnectorSettings(t)
+	connector, err := newConnector(settings, cfg, logsSink)
+	require.NoError(t, err)
+
+	err = connector.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+
+	allLogs := logsSink.AllLogs()
+	require.Len(t, allLogs, 2)
+	for i, expected := range []int64{1, 2} {
+		require.Equal(t, 1, allLogs[i].ResourceLogs().Len())
+		count, exists := allLogs[i].ResourceLogs().At(0).Resource().Attributes().Get("spaneventtolog.converted_events")
+		require.True(t, exists)
+		assert.Equal(t, expected, count.Int())
+		assert.Equal(t, int(expected), allLogs[i].LogRecordCount())
+	}
+}
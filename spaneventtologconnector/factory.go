@@ -5,6 +5,7 @@ package spaneventtologconnector // import "github.com/dev7a/otelcol-con-spaneven
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
@@ -14,12 +15,32 @@ import (
 	"github.com/dev7a/otelcol-con-spaneventtolog/spaneventtologconnector/internal/metadata"
 )
 
+// FactoryOption customizes the connectors created by the factory.
+type FactoryOption func(*factoryOptions)
+
+// factoryOptions holds the settings applied by FactoryOption values.
+type factoryOptions struct {
+	severityResolver SeverityResolver
+}
+
+// WithSeverityResolver injects a custom SeverityResolver, consulted before the configured severity sources.
+func WithSeverityResolver(resolver SeverityResolver) FactoryOption {
+	return func(o *factoryOptions) {
+		o.severityResolver = resolver
+	}
+}
+
 // NewFactory creates a factory for the span event to log connector.
-func NewFactory() connector.Factory {
+func NewFactory(opts ...FactoryOption) connector.Factory {
+	var options factoryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return connector.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
-		connector.WithTracesToLogs(createTracesToLogs, component.StabilityLevelAlpha),
+		connector.WithTracesToLogs(options.createTracesToLogs, component.StabilityLevelAlpha),
 	)
 }
 
@@ -33,11 +54,18 @@ func createDefaultConfig() component.Config {
 		},
 		AddLevel:          false, // Default to false for backward compatibility
 		SeverityAttribute: "",    // Default is empty, meaning this feature is disabled
+		HeartbeatInterval: time.Minute,
+		TimestampFallback: true,
 	}
 }
 
 // createTracesToLogs creates a traces to logs connector based on the config.
-func createTracesToLogs(_ context.Context, params connector.Settings, cfg component.Config, nextConsumer consumer.Logs) (connector.Traces, error) {
+func (o factoryOptions) createTracesToLogs(_ context.Context, params connector.Settings, cfg component.Config, nextConsumer consumer.Logs) (connector.Traces, error) {
 	c := cfg.(*config.Config)
-	return newConnector(params, *c, nextConsumer), nil
+	conn, err := newConnector(params, *c, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	conn.severityResolver = o.severityResolver
+	return conn, nil
 }